@@ -0,0 +1,270 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// programName is the name of the executable used in help texts
+const programName = "generate-td"
+
+// command is a subcommand of the command-line tool
+type command struct {
+	// name selects the command, e.g. "new"
+	name string
+	// synopsis lists the arguments shown after the name in the usage line
+	synopsis string
+	// summary is the one-line description shown in the command overview
+	summary string
+	// description is the longer text shown by the command's help
+	description string
+	// run executes the command with the arguments following its name
+	run func(cmd *command, args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in the help
+var commands = []*command{
+	{
+		name:        "generate",
+		synopsis:    "[OPTIONS]",
+		summary:     "Write a single record file in any format (default command)",
+		description: "Generates a technical debt record in the specified format. Fields given as flags are not\nprompted for; when standard input is not a terminal no prompts are shown at all.",
+		run:         runGenerate,
+	},
+	{
+		name:        "init",
+		synopsis:    "[DIRECTORY]",
+		summary:     "Create a TDR repository in the current directory",
+		description: "Creates a TDR repository in the current directory. Records are stored in DIRECTORY\n(default \"docs/tdr\"); the configuration is kept in \".tdr/\".",
+		run:         runInit,
+	},
+	{
+		name:        "new",
+		synopsis:    "[OPTIONS] [TITLE...]",
+		summary:     "Create the next numbered record in the repository",
+		description: "Creates the next numbered record (TDR-0001, TDR-0002, ...) in the repository as a Markdown\nfile named after its title, e.g. \"0007-outdated-library.md\". The remaining arguments form\nthe title.",
+		run:         runNew,
+	},
+	{
+		name:        "list",
+		synopsis:    "[OPTIONS]",
+		summary:     "List the records in the repository",
+		description: "Lists the ID, state, severity, date and title of every record in the repository.",
+		run:         runList,
+	},
+	{
+		name:        "show",
+		synopsis:    "[OPTIONS] ID",
+		summary:     "Print a record in any format",
+		description: "Renders the record with the given ID, e.g. TDR-0007 or 7, to standard output or a file.",
+		run:         runShow,
+	},
+	{
+		name:        "edit",
+		synopsis:    "[OPTIONS] ID",
+		summary:     "Change a record with flags or in $EDITOR",
+		description: "Changes the fields given as flags. Without field flags the record file is opened in\n$VISUAL or $EDITOR and validated after the editor exits.",
+		run:         runEdit,
+	},
+	{
+		name:        "transition",
+		synopsis:    "[OPTIONS] ID STATE",
+		summary:     "Move a record to another state",
+		description: "Moves the record to STATE and records the date and actor in its history. Only these\ntransitions are allowed:\n\n" + transitionTable() + "\nChanging the state with edit follows the same rules.",
+		run:         runTransition,
+	},
+	{
+		name:        "export",
+		synopsis:    "[OPTIONS]",
+		summary:     "Write all records into one file",
+		description: "Writes all records of the repository into a single file. The excel format is a workbook\nwith one row per record, a frozen header row and an autofilter, and a summary sheet\ncounting the records by state and severity. The csv format is a table with the same\ncolumns, for Google Sheets and other spreadsheets. The pdf format is a register for printing\nwith a cover page, a linked table of contents, a summary by state and severity and every\nrecord on pages of its own. -open leaves out resolved, closed and rejected records.",
+		run:         runExport,
+	},
+	{
+		name:        "import",
+		synopsis:    "-from FORMAT FILE",
+		summary:     "Create or update records from an edited export",
+		description: "Reads the records from FILE, e.g. a workbook or CSV table written by export or generate.\nThe header row names the columns; rows with an ID update that record, leaving fields without\na column unchanged, and rows without an ID create new records. Every row is validated first,\nso a file with errors leaves the repository unchanged.",
+		run:         runImport,
+	},
+	{
+		name:        "prioritize",
+		synopsis:    "[OPTIONS]",
+		summary:     "Rank the open records by Weighted Shortest Job First",
+		description: "Ranks the records by their WSJF score, (cost of delay per week + risk reduction) /\neffort estimate, highest first. Records without an effort estimate cannot be scored and\nare listed last. Resolved, closed and rejected records are left out unless -all is given.",
+		run:         runPrioritize,
+	},
+	{
+		name:        "interest",
+		synopsis:    "[OPTIONS]",
+		summary:     "Report the interest accrued by the records",
+		description: "Computes the hours lost to every record with an interest rate, from its date until\ntoday or until it was resolved, closed or rejected, and sums them by state, by\nseverity and in total.",
+		run:         runInterest,
+	},
+	{
+		name:        "report",
+		synopsis:    "[OPTIONS]",
+		summary:     "Summarize all records in a portfolio report",
+		description: "Writes a portfolio view of the repository in Markdown, HTML or JSON: the number of records\nper state and severity, the oldest open records, the open records without a proposed\nsolution and the total estimated effort of the open records.",
+		run:         runReport,
+	},
+	{
+		name:        "convert",
+		synopsis:    "[OPTIONS] SOURCE",
+		summary:     "Convert a record file or repository record to another format",
+		description: "Converts SOURCE, a Markdown, JSON or YAML record file or the ID of a repository record,\nto the format given by -format.",
+		run:         runConvert,
+	},
+	{
+		name:        "graph",
+		synopsis:    "[OPTIONS]",
+		summary:     "Export the relations between records as a DOT or Mermaid graph",
+		description: "Writes all records and their relations as a Graphviz DOT or Mermaid graph. Nodes are\ncolored by severity and shaped by state; ADRs referenced by caused-by relations are\nshown as notes.",
+		run:         runGraph,
+	},
+	{
+		name:        "lint",
+		synopsis:    "",
+		summary:     "Check all records in the repository",
+		description: "Checks that every record in the repository can be parsed, is valid, has a unique ID\nmatching its filename, only relates to existing records, has both ends of its typed\nrelations and takes part in no blocks/depends-on cycle. Exits with status 1 if\nproblems are found.",
+		run:         runLint,
+	},
+}
+
+func init() {
+	// help looks up the other commands, so it can only be added once the list exists
+	commands = append(commands, &command{
+		name:        "help",
+		synopsis:    "[COMMAND]",
+		summary:     "Show help for the tool or a command",
+		description: "Shows the list of commands, or the options of COMMAND.",
+		run:         runHelp,
+	})
+}
+
+// transitionTable describes the allowed state transitions for the help text
+func transitionTable() string {
+	var b strings.Builder
+	for _, state := range tdr.AllowedStates {
+		next := strings.Join(tdr.Transitions[state], ", ")
+		if next == "" {
+			next = "(final)"
+		}
+		fmt.Fprintf(&b, "  %-12s -> %s\n", state, next)
+	}
+	return b.String()
+}
+
+// findCommand returns the command with the given name
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// run dispatches the arguments to a command. Without a command name the
+// arguments are passed to "generate", so the flags of earlier versions still work.
+func run(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "-help", "--help":
+			printUsage()
+			return nil
+		}
+		if cmd := findCommand(args[0]); cmd != nil {
+			return cmd.run(cmd, args[1:])
+		}
+		if !strings.HasPrefix(args[0], "-") {
+			printUsage()
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+	}
+	cmd := findCommand("generate")
+	return cmd.run(cmd, args)
+}
+
+// flagSet returns a flag set whose help shows the command's usage line and description
+func (cmd *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: %s %s %s\n\n%s\n", programName, cmd.name, cmd.synopsis, cmd.description)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(out, "\nOptions:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parseFlags parses args, allowing flags to follow the positional arguments as
+// in "show TDR-0007 -format pdf", and returns the positional arguments. As
+// usual, everything after "--" is positional.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// printUsage shows the command overview
+func printUsage() {
+	out := os.Stdout
+	fmt.Fprintf(out, "Usage: %s COMMAND [OPTIONS] [ARGUMENTS]\n\n", programName)
+	fmt.Fprintf(out, "Creates and manages Technical Debt Records (TDRs).\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, `
+Run '%[1]s help COMMAND' or '%[1]s COMMAND -h' for the options of a command.
+
+Examples:
+  Generate a PDF file with a custom filename:
+        %[1]s -format pdf -output my_debt_record.pdf
+
+  Generate an Excel file with an empty template:
+        %[1]s -format excel -empty
+
+  Generate a Markdown file without prompts, e.g. in CI:
+        %[1]s -format markdown -title "Outdated Library" -author "Jane Doe" \
+                    -version 1.0.0 -state Identified -relation TDR-102 < /dev/null
+
+  Start a repository, add a record and convert it:
+        %[1]s init
+        %[1]s new -author "Jane Doe" -version 1.0.0 -state Identified Outdated Library
+        %[1]s convert -format pdf TDR-0001
+`, programName)
+}
+
+// runHelp shows the command overview or the help of a single command
+func runHelp(_ *command, args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		printUsage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	// The flags are defined by the command itself, which prints its help and exits on -h
+	return cmd.run(cmd, []string{"-h"})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+// TestParseFlags checks that flags are accepted before and after positional arguments
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantPositional []string
+		wantFormat     string
+	}{
+		{name: "Flags first", args: []string{"-format", "pdf", "TDR-0007"}, wantPositional: []string{"TDR-0007"}, wantFormat: "pdf"},
+		{name: "Flags last", args: []string{"TDR-0007", "-format", "pdf"}, wantPositional: []string{"TDR-0007"}, wantFormat: "pdf"},
+		{name: "Interspersed", args: []string{"Outdated", "-format=html", "Library"}, wantPositional: []string{"Outdated", "Library"}, wantFormat: "html"},
+		{name: "Double dash", args: []string{"--", "-format", "pdf"}, wantPositional: []string{"-format", "pdf"}, wantFormat: "markdown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			format := fs.String("format", "markdown", "")
+			got := parseFlags(fs, tt.args)
+			if !reflect.DeepEqual(got, tt.wantPositional) {
+				t.Errorf("parseFlags() = %q, want %q", got, tt.wantPositional)
+			}
+			if *format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", *format, tt.wantFormat)
+			}
+		})
+	}
+}
+
+// TestRunUnknownCommand checks that unknown commands are reported
+func TestRunUnknownCommand(t *testing.T) {
+	if err := run([]string{"frobnicate"}); err == nil {
+		t.Errorf("run() expected an error for an unknown command")
+	}
+}
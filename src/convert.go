@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runConvert renders a record file or repository record in another format
+func runConvert(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	format := fs.String("format", "", "Output format: "+strings.Join(tdr.Formats(), ", "))
+	output := fs.String("output", "", "Output filename, '-' for standard output. Defaults to the source name with the new extension in the current directory.")
+	templateFile := fs.String("template", "", "Render with this Go text/template file (NAME.EXT.tmpl) instead of -format")
+	fonts := newFontFlags(fs)
+	args = parseFlags(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		return errors.New("convert takes exactly one source")
+	}
+	if *format == "" && *templateFile == "" {
+		fs.Usage()
+		return errors.New("-format or -template is required")
+	}
+
+	renderer, err := selectRenderer(*format, *templateFile)
+	if err != nil {
+		return err
+	}
+
+	source := args[0]
+	td, path, links, err := loadSource(source)
+	if err != nil {
+		return err
+	}
+	schema, err := repositorySchema()
+	if err != nil {
+		return err
+	}
+	renderer = fonts.apply(tdr.WithSchema(tdr.WithLinks(renderer, links), schema))
+
+	filename := *output
+	if filename == "" {
+		base := filepath.Base(path)
+		filename = strings.TrimSuffix(base, filepath.Ext(base)) + renderer.Extension()
+	}
+	if err := writeRecord(renderer, td, filename); err != nil {
+		return fmt.Errorf("could not generate %s file: %w", renderer.Name(), err)
+	}
+	if filename != "-" {
+		fmt.Printf("Technical Debt record has been saved to '%s'.\n", filename)
+	}
+	return nil
+}
+
+// loadSource reads a record from a file, or from the repository if source is
+// not an existing file but a record ID. It also returns the record's file path
+// and, for repository records, the resolver linking its relations.
+func loadSource(source string) (tdr.TechnicalDebt, string, tdr.LinkResolver, error) {
+	if _, err := os.Stat(source); err == nil {
+		td, err := tdr.ReadFile(source)
+		return td, source, nil, err
+	}
+	if _, err := tdr.ParseID(source); err != nil {
+		return tdr.TechnicalDebt{}, "", nil, fmt.Errorf("%s is neither a record file nor a record ID", source)
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return tdr.TechnicalDebt{}, "", nil, err
+	}
+	rec, err := repo.Find(source)
+	if err != nil {
+		return tdr.TechnicalDebt{}, "", nil, err
+	}
+	links, err := repo.Links()
+	return rec.TechnicalDebt, rec.Path, links, err
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// exporter writes all records of a repository into a single file
+type exporter struct {
+	name      string
+	extension string
+	write     func(w io.Writer, records []tdr.Record, schema *tdr.Schema) error
+}
+
+// exporters returns the exporters by format name; the PDF register is set in
+// the fonts given by the flags
+func exporters(fonts *fontFlags) []exporter {
+	return []exporter{
+		{"excel", ".xlsx", tdr.WriteWorkbook},
+		{"csv", ".csv", tdr.WriteCSV},
+		{"pdf", ".pdf", func(w io.Writer, records []tdr.Record, _ *tdr.Schema) error {
+			return fonts.renderer().WriteRegister(w, records, time.Now())
+		}},
+	}
+}
+
+// runExport writes all repository records into one file
+func runExport(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	fonts := newFontFlags(fs)
+	exporters := exporters(fonts)
+	var names []string
+	for _, e := range exporters {
+		names = append(names, e.name)
+	}
+	format := fs.String("format", "excel", "Export format: "+strings.Join(names, ", "))
+	output := fs.String("output", "", "Output filename, '-' for standard output. Defaults to 'technical_debt_records' with the format's extension.")
+	open := fs.Bool("open", false, "Only export records that are not resolved, closed or rejected")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	index := -1
+	for i, e := range exporters {
+		if strings.EqualFold(e.name, *format) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("unsupported export format %q, supported formats are: %s", *format, strings.Join(names, ", "))
+	}
+	exporter := exporters[index]
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+	if *open {
+		records = slices.DeleteFunc(records, func(rec tdr.Record) bool { return tdr.Settled(rec.State) })
+	}
+	if len(records) == 0 {
+		return errors.New("the repository has no records to export")
+	}
+
+	filename := *output
+	if filename == "" {
+		filename = "technical_debt_records" + exporter.extension
+	}
+	if filename == "-" {
+		return exporter.write(os.Stdout, records, repo.Schema)
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := exporter.write(file, records, repo.Schema); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("%d Technical Debt records have been exported to '%s'.\n", len(records), filename)
+	return nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// stringList is a flag value that collects every occurrence of a repeatable flag
+type stringList []string
+
+// String implements flag.Value
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+// Set implements flag.Value
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// recordFlags holds the command-line flags for the fields of a technical debt record
+type recordFlags struct {
+	fs             *flag.FlagSet
+	title          string
+	author         string
+	version        string
+	date           string
+	state          string
+	relations      stringList
+	summary        string
+	context        string
+	impactTech     string
+	impactBus      string
+	symptoms       string
+	severity       string
+	potentialRisks string
+	proposedSol    string
+	costDelay      string
+	effort         string
+	effortEstimate float64
+	costOfDelay    float64
+	riskReduction  float64
+	interestRate   float64
+	dependencies   string
+	additional     string
+	fields         stringList
+}
+
+// newRecordFlags registers one flag per record field on fs
+func newRecordFlags(fs *flag.FlagSet) *recordFlags {
+	f := &recordFlags{fs: fs}
+	fs.StringVar(&f.title, "title", "", "Title of the technical debt")
+	fs.StringVar(&f.author, "author", "", "Author of the document")
+	fs.StringVar(&f.version, "version", "", "Version (e.g., 1.0.0)")
+	fs.StringVar(&f.date, "date", "", "Date (YYYY-MM-DD), defaults to today")
+	fs.StringVar(&f.state, "state", "", "State: "+strings.Join(tdr.AllowedStates, ", "))
+	fs.Var(&f.relations, "relation", "Related record as '[KIND] ID', e.g. 'TDR-0002' or 'blocks TDR-0005' (repeatable)")
+	fs.StringVar(&f.summary, "summary", "", "Summary")
+	fs.StringVar(&f.context, "context", "", "Context")
+	fs.StringVar(&f.impactTech, "impact-tech", "", "Technical impact")
+	fs.StringVar(&f.impactBus, "impact-bus", "", "Business impact")
+	fs.StringVar(&f.symptoms, "symptoms", "", "Symptoms")
+	fs.StringVar(&f.severity, "severity", "", "Severity: "+strings.Join(tdr.AllowedSeverities, ", "))
+	fs.StringVar(&f.potentialRisks, "risks", "", "Potential risks")
+	fs.StringVar(&f.proposedSol, "solution", "", "Proposed solution")
+	fs.StringVar(&f.costDelay, "cost-of-delay", "", "Cost of delay")
+	fs.StringVar(&f.effort, "effort", "", "Effort to resolve")
+	fs.Float64Var(&f.effortEstimate, "effort-estimate", 0, "Effort to resolve in person-days or story points, used by prioritize")
+	fs.Float64Var(&f.costOfDelay, "cost-of-delay-per-week", 0, "Cost of delay per week, used by prioritize")
+	fs.Float64Var(&f.riskReduction, "risk-reduction", 0, "Risk reduction achieved by resolving the debt, used by prioritize")
+	fs.Float64Var(&f.interestRate, "interest-rate", 0, "Hours lost per week while the debt remains, used by interest")
+	fs.StringVar(&f.dependencies, "dependencies", "", "Dependencies")
+	fs.StringVar(&f.additional, "notes", "", "Additional notes")
+	fs.Var(&f.fields, "field", "Custom field declared in .tdr/schema.yaml as 'NAME=VALUE', e.g. 'Component=Backend' (repeatable)")
+	return f
+}
+
+// provided returns the names of the record flags that were set on the command line
+func (f *recordFlags) provided() map[string]bool {
+	set := make(map[string]bool)
+	f.fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+	return set
+}
+
+// customProvided returns the lower-case names of the custom fields set with -field
+func (f *recordFlags) customProvided() map[string]bool {
+	set := make(map[string]bool)
+	for _, value := range f.fields {
+		name, _, _ := strings.Cut(value, "=")
+		set[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return set
+}
+
+// apply copies the values of all flags that were set into td
+func (f *recordFlags) apply(td *tdr.TechnicalDebt) error {
+	set := f.provided()
+	assign := func(name string, dst *string, value string) {
+		if set[name] {
+			*dst = strings.TrimSpace(value)
+		}
+	}
+	assign("title", &td.Title, f.title)
+	assign("author", &td.Author, f.author)
+	assign("version", &td.Version, f.version)
+	assign("date", &td.Date, f.date)
+	if set["state"] {
+		state, err := tdr.ParseState(f.state)
+		if err != nil {
+			return err
+		}
+		td.State = state
+	}
+	if set["relation"] {
+		td.Relations = nil
+		for _, value := range f.relations {
+			rel, err := tdr.ParseRelation(value)
+			if err != nil {
+				return err
+			}
+			td.Relations = append(td.Relations, rel)
+		}
+	}
+	assign("summary", &td.Summary, f.summary)
+	assign("context", &td.Context, f.context)
+	assign("impact-tech", &td.ImpactTech, f.impactTech)
+	assign("impact-bus", &td.ImpactBus, f.impactBus)
+	assign("symptoms", &td.Symptoms, f.symptoms)
+	if set["severity"] {
+		td.Severity = ""
+		if strings.TrimSpace(f.severity) != "" {
+			severity, err := tdr.ParseSeverity(f.severity)
+			if err != nil {
+				return err
+			}
+			td.Severity = severity
+		}
+	}
+	assign("risks", &td.PotentialRisks, f.potentialRisks)
+	assign("solution", &td.ProposedSol, f.proposedSol)
+	assign("cost-of-delay", &td.CostDelay, f.costDelay)
+	assign("effort", &td.Effort, f.effort)
+	if set["effort-estimate"] {
+		td.EffortEstimate = f.effortEstimate
+	}
+	if set["cost-of-delay-per-week"] {
+		td.CostOfDelayWeekly = f.costOfDelay
+	}
+	if set["risk-reduction"] {
+		td.RiskReduction = f.riskReduction
+	}
+	if set["interest-rate"] {
+		td.InterestRate = f.interestRate
+	}
+	assign("dependencies", &td.Dependencies, f.dependencies)
+	assign("notes", &td.Additional, f.additional)
+	for _, field := range f.fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid field %q, expected NAME=VALUE", field)
+		}
+		td.CustomFields.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return nil
+}
+
+// fontFlags holds the flags selecting the TrueType fonts of PDF output
+type fontFlags struct {
+	regular string
+	bold    string
+}
+
+// newFontFlags registers the PDF font flags on fs
+func newFontFlags(fs *flag.FlagSet) *fontFlags {
+	f := &fontFlags{}
+	fs.StringVar(&f.regular, "pdf-font", "", "TrueType font file for PDF output (default: the bundled DejaVu Sans)")
+	fs.StringVar(&f.bold, "pdf-bold-font", "", "TrueType font file for PDF headings (default: the -pdf-font file)")
+	return f
+}
+
+// renderer returns a PDF renderer using the fonts
+func (f *fontFlags) renderer() tdr.PDFRenderer {
+	return tdr.PDFRenderer{FontFile: f.regular, BoldFontFile: f.bold}
+}
+
+// apply replaces a PDF renderer by one using the fonts; other renderers are returned unchanged
+func (f *fontFlags) apply(r tdr.Renderer) tdr.Renderer {
+	if _, ok := r.(tdr.PDFRenderer); ok {
+		return f.renderer()
+	}
+	return r
+}
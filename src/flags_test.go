@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// TestRecordFlagsApply checks that set flags are copied into the record
+func TestRecordFlagsApply(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fields := newRecordFlags(fs)
+	args := []string{
+		"-title", "Outdated Library",
+		"-author", "Jane Doe",
+		"-version", "1.0.0",
+		"-date", "2024-04-15",
+		"-state", "in progress",
+		"-relation", "TDR-102",
+		"-relation", "TDR-103",
+		"-severity", "high",
+		"-notes", "Training for the development team.",
+		"-field", "Component = Backend",
+		"-field", "Jira Key=PAY-1234",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	td := tdr.TechnicalDebt{Summary: "kept"}
+	if err := fields.apply(&td); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	want := tdr.TechnicalDebt{
+		Title:        "Outdated Library",
+		Author:       "Jane Doe",
+		Version:      "1.0.0",
+		Date:         "2024-04-15",
+		State:        "In Progress",
+		Relations:    []tdr.Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
+		Summary:      "kept",
+		Severity:     "High",
+		Additional:   "Training for the development team.",
+		CustomFields: tdr.Fields{{Name: "Component", Value: "Backend"}, {Name: "Jira Key", Value: "PAY-1234"}},
+	}
+	if !reflect.DeepEqual(td, want) {
+		t.Errorf("apply() = %+v, want %+v", td, want)
+	}
+
+	provided := fields.provided()
+	if !provided["relation"] || provided["summary"] {
+		t.Errorf("provided() = %v, want relation set and summary unset", provided)
+	}
+	if custom := fields.customProvided(); !custom["component"] || !custom["jira key"] {
+		t.Errorf("customProvided() = %v, want component and jira key", custom)
+	}
+}
+
+// TestRecordFlagsInvalid checks that an unknown state, severity or relation kind
+// and a custom field without value are rejected
+func TestRecordFlagsInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-state", "Done"},
+		{"-severity", "urgent"},
+		{"-relation", "fixes TDR-102"},
+		{"-field", "Component"},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fields := newRecordFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		var td tdr.TechnicalDebt
+		if err := fields.apply(&td); err == nil {
+			t.Errorf("apply() expected an error for %q", args)
+		}
+	}
+}
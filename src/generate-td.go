@@ -2,79 +2,28 @@ package main
 
 import (
 	"bufio"
+	"cmp"
 	"errors"
-	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv" // Added strconv
 	"strings"
 	"time"
 
-	"github.com/phpdave11/gofpdf"
-	"github.com/xuri/excelize/v2"
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+	"golang.org/x/term"
 )
 
-// TechnicalDebt represents a technical debt record
-type TechnicalDebt struct {
-	Title          string
-	Author         string
-	Version        string
-	Date           string
-	State          string
-	Relations      []string
-	Summary        string
-	Context        string
-	ImpactTech     string
-	ImpactBus      string
-	Symptoms       string
-	Severity       string
-	PotentialRisks string
-	ProposedSol    string
-	CostDelay      string
-	Effort         string
-	Dependencies   string
-	Additional     string
-	Empty          bool
-}
-
-// AllowedStates defines the possible states of a Technical Debt Record
-var AllowedStates = []string{
-	"Identified",
-	"Analyzed",
-	"Approved",
-	"In Progress",
-	"Resolved",
-	"Closed",
-	"Rejected",
-}
-
-// validateTechnicalDebt ensures all required fields are present
-func validateTechnicalDebt(td TechnicalDebt) error {
-	if td.Title == "" {
-		return errors.New("Title is required")
-	}
-	if td.Author == "" {
-		return errors.New("Author is required")
-	}
-	if td.Version == "" {
-		return errors.New("Version is required")
-	}
-	if td.Date == "" {
-		return errors.New("Date is required")
-	}
-	if td.State == "" {
-		return errors.New("State is required")
-	}
-	return nil
-}
+// stdin is shared by all prompts so that buffered input is not lost between them
+var stdin = bufio.NewReader(os.Stdin)
 
 // getInput prompts the user for input and returns the entered value
 func getInput(prompt string, required bool) (string, error) {
-	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print(prompt)
-		input, err := reader.ReadString('\n')
+		input, err := stdin.ReadString('\n')
 		if err != nil {
 			return "", err
 		}
@@ -90,7 +39,7 @@ func getInput(prompt string, required bool) (string, error) {
 // getState prompts the user to select a state from the allowed states
 func getState() (string, error) {
 	fmt.Println("Select the State of the Technical Debt:")
-	for i, state := range AllowedStates {
+	for i, state := range tdr.AllowedStates {
 		fmt.Printf("  %d) %s\n", i+1, state)
 	}
 	for {
@@ -99,557 +48,441 @@ func getState() (string, error) {
 			return "", err
 		}
 		index, err := strconv.Atoi(input) // Use strconv to convert string to int
-		if err != nil || index < 1 || index > len(AllowedStates) {
+		if err != nil || index < 1 || index > len(tdr.AllowedStates) {
 			fmt.Println("Invalid selection. Please enter a valid number.")
 			continue
 		}
-		return AllowedStates[index-1], nil
+		return tdr.AllowedStates[index-1], nil
 	}
 }
 
-// getRelations prompts the user to enter related TDR IDs
-func getRelations() ([]string, error) {
-	var relations []string
-	fmt.Println("Enter related Technical Debt IDs (leave blank to finish):")
+// getSeverity prompts the user to select a severity; it is optional, so a blank entry selects none
+func getSeverity() (string, error) {
+	fmt.Println("Select the Severity of the Technical Debt:")
+	for i, severity := range tdr.AllowedSeverities {
+		fmt.Printf("  %d) %s\n", i+1, severity)
+	}
 	for {
-		rel, err := getInput(" - Related TD ID: ", false)
+		input, err := getInput("Enter the number corresponding to the severity [Leave blank to skip]: ", false)
+		if err != nil {
+			return "", err
+		}
+		if input == "" {
+			return "", nil
+		}
+		index, err := strconv.Atoi(input)
+		if err != nil || index < 1 || index > len(tdr.AllowedSeverities) {
+			fmt.Println("Invalid selection. Please enter a valid number.")
+			continue
+		}
+		return tdr.AllowedSeverities[index-1], nil
+	}
+}
+
+// getRelations prompts the user to enter related TDR IDs, optionally preceded by a relation kind
+func getRelations() ([]tdr.Relation, error) {
+	var relations []tdr.Relation
+	fmt.Println("Enter related Technical Debt IDs, optionally preceded by a kind such as")
+	fmt.Println("'blocks', 'depends-on', 'supersedes', 'duplicates' or 'caused-by' (leave blank to finish):")
+	for {
+		input, err := getInput(" - Related TD ID: ", false)
 		if err != nil {
 			return nil, err
 		}
-		if rel == "" {
+		if input == "" {
 			break
 		}
+		rel, err := tdr.ParseRelation(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
 		relations = append(relations, rel)
 	}
 	return relations, nil
 }
 
-// generateASCII generates the Plain ASCII content
-func generateASCII(td TechnicalDebt) string {
-	relationsFormatted := "None"
-	if len(td.Relations) > 0 {
-		var rels []string
-		for _, rel := range td.Relations {
-			rels = append(rels, fmt.Sprintf("- %s", rel))
-		}
-		relationsFormatted = strings.Join(rels, "\n")
-	}
-
-	if td.Empty {
-		return fmt.Sprintf(`Technical Debt Record
-====================
-    
-Title:
-------
-[Enter Title Here]
-    
-Author:
--------
-[Enter Author Here]
-    
-Version:
---------
-[Enter Version Here]
-    
-Date:
------
-[Enter Date Here]
-    
-State:
-------
-[Enter State Here]
-    
-Relations:
-----------
-%s
-    
-Summary:
---------
-*A brief overview of the technical debt, explaining the problem in one or two sentences.*
-    
-Context:
---------
-*Provide the historical context and reasons why this technical debt exists.*
-    
-Impact:
--------
-Technical Impact:
-- *Describe the technical impact.*
-    
-Business Impact:
-- *Describe the business impact.*
-    
-Symptoms:
----------
-*List specific signs that indicate the presence of technical debt.*
-    
-Severity:
----------
-*[Enter Severity Here: Critical / High / Medium / Low]*
-    
-Potential Risks:
-----------------
-*Potential security vulnerabilities leading to data breaches.*
-    
-Proposed Solution:
--------------------
-*Describe how to resolve the technical debt.*
-    
-Cost of Delay:
----------------
-*Explain the consequences of delaying the resolution of the technical debt.*
-    
-Effort to Resolve:
--------------------
-*Estimate the time, resources, and effort needed to address the debt.*
-    
-Dependencies:
--------------
-*List any dependencies or blockers that need to be resolved before tackling the debt.*
-    
-Additional Notes:
------------------
-*Any other relevant information or considerations.*
-`, relationsFormatted)
-	}
-
-	// Normal ASCII generation
-	return fmt.Sprintf(`Technical Debt Record
-====================
-    
-Title:
-------
-%s
-    
-Author:
--------
-%s
-    
-Version:
---------
-%s
-    
-Date:
------
-%s
-    
-State:
-------
-%s
-    
-Relations:
-----------
-%s
-    
-Summary:
---------
-%s
-    
-Context:
---------
-%s
-    
-Impact:
--------
-Technical Impact:
-- %s
-    
-Business Impact:
-- %s
-    
-Symptoms:
----------
-%s
-    
-Severity:
----------
-%s
-    
-Potential Risks:
-----------------
-%s
-    
-Proposed Solution:
--------------------
-%s
-    
-Cost of Delay:
----------------
-%s
-    
-Effort to Resolve:
--------------------
-%s
-    
-Dependencies:
--------------
-%s
-    
-Additional Notes:
------------------
-%s
-`, td.Title, td.Author, td.Version, td.Date, td.State, relationsFormatted, td.Summary, td.Context,
-		td.ImpactTech, td.ImpactBus, td.Symptoms, td.Severity, td.PotentialRisks, td.ProposedSol,
-		td.CostDelay, td.Effort, td.Dependencies, td.Additional)
-}
-
-// generateMarkdown generates the Markdown content
-func generateMarkdown(td TechnicalDebt) string {
-	relationsFormatted := "None"
-	if len(td.Relations) > 0 {
-		var rels []string
-		for _, rel := range td.Relations {
-			rels = append(rels, fmt.Sprintf("- [%s](#)", rel))
+// collectRecord builds a record from the input file (if any) and the flags, and
+// prompts for the remaining fields, including the custom fields of the schema,
+// when running interactively without an input file
+func collectRecord(fields *recordFlags, input string, schema *tdr.Schema) (tdr.TechnicalDebt, error) {
+	var td tdr.TechnicalDebt
+	if input != "" {
+		var err error
+		td, err = readInput(input)
+		if err != nil {
+			return td, fmt.Errorf("could not read input '%s': %w", input, err)
 		}
-		relationsFormatted = strings.Join(rels, "\n")
 	}
 
-	if td.Empty {
-		return fmt.Sprintf(`# Technical Debt Record
-
-## Title
-
-**[Enter Title Here]**
-
-## Author
-
-[Enter Author Here]
-
-## Version
-
-**[Enter Version Here]**
-
-## Date
-
-[Enter Date Here]
-
-## State
-
-[Enter State Here]
-
-## Relations
-
-%s
-
-## Summary
-
-*A brief overview of the technical debt, explaining the problem in one or two sentences.*
-
-## Context
-
-*Provide the historical context and reasons why this technical debt exists.*
-
-## Impact
-
-### Technical Impact
-
-*Describe how the debt affects the system’s performance, scalability, or maintainability.*
-
-### Business Impact
-
-*Explain how the debt affects the business, such as increased risk, customer dissatisfaction, or slower feature delivery.*
-
-## Symptoms
-
-*List specific signs that indicate the presence of technical debt.*
-
-## Severity
-
-*[Enter Severity Here: Critical / High / Medium / Low]*
-
-## Potential Risks
-
-*Potential security vulnerabilities leading to data breaches.*
-
-## Proposed Solution
-
-*Describe how to resolve the technical debt.*
-
-## Cost of Delay
-
-*Explain the consequences of delaying the resolution of the technical debt.*
-
-## Effort to Resolve
-
-*Estimate the time, resources, and effort needed to address the debt.*
-
-## Dependencies
-
-*List any dependencies or blockers that need to be resolved before tackling the debt.*
-
-## Additional Notes
-
-*Any other relevant information or considerations.*
-`, relationsFormatted)
+	if err := fields.apply(&td); err != nil {
+		return td, fmt.Errorf("invalid input: %w", err)
 	}
 
-	// Normal Markdown generation
-	return fmt.Sprintf(`# Technical Debt Record
-
-## Title
-
-**%s**
-
-## Author
-
-%s
-
-## Version
-
-%s
-
-## Date
-
-%s
-
-## State
-
-%s
-
-## Relations
-
-%s
-
-## Summary
-
-%s
-
-## Context
-
-%s
-
-## Impact
-
-### Technical Impact
-
-%s
-
-### Business Impact
+	if input == "" && isTerminal(os.Stdin) {
+		if err := promptRecord(&td, fields.provided()); err != nil {
+			return td, err
+		}
+		if err := promptFields(&td, schema, fields.customProvided()); err != nil {
+			return td, err
+		}
+	} else if td.Date == "" {
+		td.Date = time.Now().Format(tdr.DateFormat)
+	}
 
-%s
+	// Validate inputs
+	schema.Normalize(&td)
+	if err := tdr.Validate(td); err != nil {
+		return td, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := schema.Validate(td); err != nil {
+		return td, fmt.Errorf("validation failed: %w", err)
+	}
+	return td, nil
+}
 
-## Symptoms
+// readInput loads a record from a JSON or YAML file, or from stdin if path is "-"
+func readInput(path string) (tdr.TechnicalDebt, error) {
+	var td tdr.TechnicalDebt
+	var err error
+	if path == "-" {
+		td, err = tdr.Decode(stdin, "")
+	} else {
+		var file *os.File
+		file, err = os.Open(path)
+		if err != nil {
+			return td, err
+		}
+		defer file.Close()
+		td, err = tdr.Decode(file, path)
+	}
+	if err != nil {
+		return td, err
+	}
 
-%s
+	// Accept states regardless of case, as the prompts and flags do; severities
+	// are normalized by the decoder
+	if td.State != "" {
+		if td.State, err = tdr.ParseState(td.State); err != nil {
+			return td, err
+		}
+	}
+	return td, nil
+}
 
-## Severity
+// isTerminal reports whether f is connected to an interactive terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
 
-%s
+// promptRecord asks for every field of td that was not provided on the command line
+func promptRecord(td *tdr.TechnicalDebt, provided map[string]bool) error {
+	var err error
 
-## Potential Risks
+	// prompt asks for a single text field unless it was already provided
+	prompt := func(name string, dst *string, text string, required bool) error {
+		if provided[name] {
+			return nil
+		}
+		value, err := getInput(text, required)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", strings.ReplaceAll(name, "-", " "), err)
+		}
+		*dst = value
+		return nil
+	}
 
-%s
+	if err := prompt("title", &td.Title, "Enter the Title of the Technical Debt: ", true); err != nil {
+		return err
+	}
+	if err := prompt("author", &td.Author, "Enter the Author of the Document: ", true); err != nil {
+		return err
+	}
+	if err := prompt("version", &td.Version, "Enter the Version (e.g., 1.0.0): ", true); err != nil {
+		return err
+	}
 
-## Proposed Solution
+	// Prompt for Date with default as today
+	if !provided["date"] {
+		for {
+			dateInput, err := getInput("Enter the Date (YYYY-MM-DD) [Leave blank for today]: ", false)
+			if err != nil {
+				return fmt.Errorf("could not read date: %w", err)
+			}
+			if dateInput == "" {
+				td.Date = time.Now().Format(tdr.DateFormat)
+				break
+			}
+			// Validate date format
+			if _, err := time.Parse(tdr.DateFormat, dateInput); err != nil {
+				fmt.Println("Invalid date format. Please use YYYY-MM-DD.")
+				continue
+			}
+			td.Date = dateInput
+			break
+		}
+	}
 
-%s
+	// Get State
+	if !provided["state"] {
+		td.State, err = getState()
+		if err != nil {
+			return fmt.Errorf("could not read state: %w", err)
+		}
+	}
 
-## Cost of Delay
+	if !provided["relation"] {
+		td.Relations, err = getRelations()
+		if err != nil {
+			return fmt.Errorf("could not read relations: %w", err)
+		}
+	}
 
-%s
+	// Additional fields; severity is selected from a list after the symptoms
+	optional := []struct {
+		name string
+		dst  *string
+		text string
+	}{
+		{"summary", &td.Summary, "Enter Summary: "},
+		{"context", &td.Context, "Enter Context: "},
+		{"impact-tech", &td.ImpactTech, "Enter Technical Impact: "},
+		{"impact-bus", &td.ImpactBus, "Enter Business Impact: "},
+		{"symptoms", &td.Symptoms, "Enter Symptoms: "},
+		{"risks", &td.PotentialRisks, "Enter Potential Risks: "},
+		{"solution", &td.ProposedSol, "Enter Proposed Solution: "},
+		{"cost-of-delay", &td.CostDelay, "Enter Cost of Delay: "},
+		{"effort", &td.Effort, "Enter Effort to Resolve: "},
+		{"dependencies", &td.Dependencies, "Enter Dependencies: "},
+		{"notes", &td.Additional, "Enter Additional Notes: "},
+	}
+	for _, field := range optional {
+		if err := prompt(field.name, field.dst, field.text, false); err != nil {
+			return err
+		}
+		if field.name == "symptoms" && !provided["severity"] {
+			td.Severity, err = getSeverity()
+			if err != nil {
+				return fmt.Errorf("could not read severity: %w", err)
+			}
+		}
+		if field.name == "effort" {
+			if err := promptEstimates(td, provided); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-## Effort to Resolve
+// promptEstimates asks for the numeric estimates used by prioritize and interest; each may be left blank
+func promptEstimates(td *tdr.TechnicalDebt, provided map[string]bool) error {
+	estimates := []struct {
+		name string
+		dst  *float64
+		text string
+	}{
+		{"effort-estimate", &td.EffortEstimate, "Enter Effort Estimate in person-days or story points [Leave blank to skip]: "},
+		{"cost-of-delay-per-week", &td.CostOfDelayWeekly, "Enter Cost of Delay per Week [Leave blank to skip]: "},
+		{"risk-reduction", &td.RiskReduction, "Enter Risk Reduction [Leave blank to skip]: "},
+		{"interest-rate", &td.InterestRate, "Enter Interest Rate in hours lost per week [Leave blank to skip]: "},
+	}
+	for _, e := range estimates {
+		if provided[e.name] {
+			continue
+		}
+		for {
+			input, err := getInput(e.text, false)
+			if err != nil {
+				return fmt.Errorf("could not read %s: %w", strings.ReplaceAll(e.name, "-", " "), err)
+			}
+			if input == "" {
+				break
+			}
+			value, err := strconv.ParseFloat(input, 64)
+			if err != nil || value < 0 {
+				fmt.Println("Invalid number. Please enter a number of 0 or more.")
+				continue
+			}
+			*e.dst = value
+			break
+		}
+	}
+	return nil
+}
 
-%s
+// promptFields asks for the custom fields of the schema that were not provided
+// on the command line, with their prompt text. Fields with allowed values are
+// selected from a list.
+func promptFields(td *tdr.TechnicalDebt, schema *tdr.Schema, provided map[string]bool) error {
+	if schema == nil {
+		return nil
+	}
+	for _, spec := range schema.Fields {
+		if provided[strings.ToLower(spec.Name)] {
+			continue
+		}
+		text := cmp.Or(spec.Prompt, "Enter the "+spec.Name)
+		switch {
+		case len(spec.Values) > 0:
+			fmt.Println(promptLine(text))
+			for i, value := range spec.Values {
+				fmt.Printf("  %d) %s\n", i+1, value)
+			}
+			text = "Enter the number corresponding to the " + spec.Name
+		case spec.Type == tdr.FieldDate:
+			text += " (YYYY-MM-DD)"
+		case spec.Type == tdr.FieldNumber:
+			text += " (a number)"
+		}
+		if !spec.Required {
+			text += " [Leave blank to skip]"
+		}
 
-## Dependencies
+		for {
+			input, err := getInput(promptLine(text)+" ", spec.Required)
+			if err != nil {
+				return fmt.Errorf("could not read %s: %w", spec.Name, err)
+			}
+			if len(spec.Values) > 0 && input != "" {
+				index, err := strconv.Atoi(input)
+				if err != nil || index < 1 || index > len(spec.Values) {
+					fmt.Println("Invalid selection. Please enter a valid number.")
+					continue
+				}
+				input = spec.Values[index-1]
+			}
+			if err := spec.Check(input); err != nil {
+				fmt.Println(err)
+				continue
+			}
+			td.CustomFields.Set(spec.Name, input)
+			break
+		}
+	}
+	return nil
+}
 
-%s
+// promptLine ends a prompt with a colon unless it ends with a question mark or colon
+func promptLine(text string) string {
+	if strings.HasSuffix(text, "?") || strings.HasSuffix(text, ":") {
+		return text
+	}
+	return text + ":"
+}
 
-## Additional Notes
+// lookupRenderer returns the renderer for a format name or an error listing the
+// supported formats. The output templates of the repository in the working
+// directory come first, so that they can replace built-in formats.
+func lookupRenderer(format string) (tdr.Renderer, error) {
+	templates, err := repositoryTemplates()
+	if err != nil {
+		return nil, err
+	}
+	names := tdr.Formats()
+	for _, t := range templates {
+		if strings.EqualFold(t.Name(), format) {
+			return t, nil
+		}
+		if !slices.Contains(names, t.Name()) {
+			names = append(names, t.Name())
+		}
+	}
+	renderer, ok := tdr.Lookup(format)
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q, supported formats are: %s", format, strings.Join(names, ", "))
+	}
+	return renderer, nil
+}
 
-%s
-`, td.Title, td.Author, td.Version, td.Date, td.State, relationsFormatted, td.Summary, td.Context,
-		td.ImpactTech, td.ImpactBus, td.Symptoms, td.Severity, td.PotentialRisks, td.ProposedSol,
-		td.CostDelay, td.Effort, td.Dependencies, td.Additional)
+// selectRenderer returns the renderer for the template file if one is given,
+// or else for the format, see lookupRenderer
+func selectRenderer(format, templateFile string) (tdr.Renderer, error) {
+	if templateFile != "" {
+		return tdr.LoadTemplate(templateFile)
+	}
+	return lookupRenderer(format)
 }
 
-// generatePDF generates a PDF file using the gofpdf library
-func generatePDF(td TechnicalDebt, filename string) error {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	// Set font and size for the title
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(40, 10, "Technical Debt Record")
-	pdf.Ln(12)
-
-	// Add sections to PDF
-	addPDFSection(pdf, "Title", td.Title)
-	addPDFSection(pdf, "Author", td.Author)
-	addPDFSection(pdf, "Version", td.Version)
-	addPDFSection(pdf, "Date", td.Date)
-	addPDFSection(pdf, "State", td.State)
-	addPDFSection(pdf, "Relations", strings.Join(td.Relations, ", "))
-	addPDFSection(pdf, "Summary", td.Summary)
-	addPDFSection(pdf, "Context", td.Context)
-	addPDFSection(pdf, "Technical Impact", td.ImpactTech)
-	addPDFSection(pdf, "Business Impact", td.ImpactBus)
-	addPDFSection(pdf, "Symptoms", td.Symptoms)
-	addPDFSection(pdf, "Severity", td.Severity)
-	addPDFSection(pdf, "Potential Risks", td.PotentialRisks)
-	addPDFSection(pdf, "Proposed Solution", td.ProposedSol)
-	addPDFSection(pdf, "Cost of Delay", td.CostDelay)
-	addPDFSection(pdf, "Effort to Resolve", td.Effort)
-	addPDFSection(pdf, "Dependencies", td.Dependencies)
-	addPDFSection(pdf, "Additional Notes", td.Additional)
-
-	// Output the PDF
-	err := pdf.OutputFileAndClose(filename)
+// repositorySchema returns the schema of the repository in the working
+// directory; outside a repository there is none
+func repositorySchema() (*tdr.Schema, error) {
+	repo, err := tdr.OpenRepository(".")
+	if errors.Is(err, tdr.ErrNoRepository) {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("error saving PDF file: %w", err)
+		return nil, err
 	}
-	return nil
+	return repo.Schema, nil
 }
 
-// addPDFSection adds a section to a PDF document
-func addPDFSection(pdf *gofpdf.Fpdf, title, content string) {
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, title+":")
-	pdf.Ln(8)
-	pdf.SetFont("Arial", "", 12)
-	pdf.MultiCell(0, 10, content, "", "", false)
-	pdf.Ln(6)
+// repositoryTemplates returns the output templates of the repository in the
+// working directory; outside a repository there are none
+func repositoryTemplates() ([]tdr.TemplateRenderer, error) {
+	repo, err := tdr.OpenRepository(".")
+	if errors.Is(err, tdr.ErrNoRepository) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return repo.Templates()
 }
 
-// generateExcel generates an Excel file using the excelize library
-func generateExcel(td TechnicalDebt, filename string) error {
-	f := excelize.NewFile()
-
-	// Create a sheet
-	sheet := "TechnicalDebt"
-	index, _ := f.NewSheet(sheet) // Corrected: In excelize v2, NewSheet returns only an int
-
-	// Set headers
-	headers := []string{
-		"Title",
-		"Author",
-		"Version",
-		"Date",
-		"State",
-		"Relations",
-		"Summary",
-		"Context",
-		"Technical Impact",
-		"Business Impact",
-		"Symptoms",
-		"Severity",
-		"Potential Risks",
-		"Proposed Solution",
-		"Cost of Delay",
-		"Effort to Resolve",
-		"Dependencies",
-		"Additional Notes",
-	}
-
-	for i, header := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheet, cell, header)
-	}
-
-	// Set values
-	values := []string{
-		td.Title,
-		td.Author,
-		td.Version,
-		td.Date,
-		td.State,
-		strings.Join(td.Relations, ", "),
-		td.Summary,
-		td.Context,
-		td.ImpactTech,
-		td.ImpactBus,
-		td.Symptoms,
-		td.Severity,
-		td.PotentialRisks,
-		td.ProposedSol,
-		td.CostDelay,
-		td.Effort,
-		td.Dependencies,
-		td.Additional,
-	}
-
-	for i, value := range values {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 2)
-		f.SetCellValue(sheet, cell, value)
-	}
-
-	// Set active sheet
-	f.SetActiveSheet(index)
-
-	// Save the Excel file
-	if err := f.SaveAs(filename); err != nil {
-		return fmt.Errorf("error saving Excel file: %w", err)
+// writeRecord renders the record with the given renderer into filename, or to
+// standard output if filename is "-"
+func writeRecord(renderer tdr.Renderer, td tdr.TechnicalDebt, filename string) error {
+	if filename == "-" {
+		return renderer.Render(os.Stdout, td)
 	}
-	return nil
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := renderer.Render(file, td); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
 
 func main() {
-	// Define command-line flags
-	formatPtr := flag.String("format", "markdown", "Output format: markdown, ascii, pdf, excel")
-	filenamePtr := flag.String("output", "", "Output filename (optional)")
-	emptyPtr := flag.Bool("empty", false, "Generate an empty template")
-	flag.Parse()
-
-	// Check if help is requested
-	if len(os.Args) > 1 {
-		for _, arg := range os.Args[1:] {
-			if arg == "-h" || arg == "--help" {
-				usageText := `Usage: generate_td [OPTIONS]
-
-Generates a technical debt record in the specified format.
-
-Options:
-  -format string
-        Output format: markdown, ascii, pdf, excel (default "markdown")
-  -output string
-        Output filename (optional). If not provided, a default filename with the appropriate extension is generated.
-  -empty
-        Generate an empty template with placeholders without prompting for input.
-  -h, --help
-        Show this help message and exit.
-
-Examples:
-  Generate a Markdown file:
-        generate_td -format markdown
-
-  Generate a PDF file with a custom filename:
-        generate_td -format pdf -output my_debt_record.pdf
-
-  Generate an Excel file with an empty template:
-        generate_td -format excel -empty
-
-  Show help:
-        generate_td --help
-`
-				fmt.Print(usageText)
-				return
-			}
-		}
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
+}
 
-	// Supported formats
-	supportedFormats := map[string]bool{
-		"markdown": true,
-		"ascii":    true,
-		"pdf":      true,
-		"excel":    true,
+// runGenerate writes a single record file in the requested format, as the tool
+// always did before records were kept in a repository
+func runGenerate(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	formatPtr := fs.String("format", "markdown", "Output format: "+strings.Join(tdr.Formats(), ", "))
+	filenamePtr := fs.String("output", "", "Output filename. If not provided, a default filename with the appropriate extension is generated.")
+	emptyPtr := fs.Bool("empty", false, "Generate an empty template with placeholders without prompting for input")
+	inputPtr := fs.String("input", "", "Read the record from a JSON or YAML file ('-' for stdin)")
+	templatePtr := fs.String("template", "", "Render with this Go text/template file (NAME.EXT.tmpl) instead of -format")
+	fields := newRecordFlags(fs)
+	fonts := newFontFlags(fs)
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
 	}
 
 	// Validate format
 	format := strings.ToLower(*formatPtr)
-	if !supportedFormats[format] {
-		fmt.Println("Unsupported format. Supported formats are: markdown, ascii, pdf, excel")
-		return
+	renderer, err := selectRenderer(format, *templatePtr)
+	if err != nil {
+		return err
 	}
-
-	// Map formats to file extensions
-	formatExtensions := map[string]string{
-		"markdown": ".md",
-		"ascii":    ".txt",
-		"pdf":      ".pdf",
-		"excel":    ".xlsx",
+	format = renderer.Name()
+	schema, err := repositorySchema()
+	if err != nil {
+		return err
 	}
+	renderer = fonts.apply(tdr.WithSchema(renderer, schema))
 
 	// Determine output filename
 	var filename string
@@ -657,11 +490,11 @@ Examples:
 		ext := filepath.Ext(*filenamePtr)
 		if ext == "" {
 			// Append the correct extension
-			filename = *filenamePtr + formatExtensions[format]
-			fmt.Printf("No file extension provided. Appending '%s' to the filename.\n", formatExtensions[format])
+			filename = *filenamePtr + renderer.Extension()
+			fmt.Printf("No file extension provided. Appending '%s' to the filename.\n", renderer.Extension())
 		} else {
 			// Check if the extension matches the format
-			expectedExt := formatExtensions[format]
+			expectedExt := renderer.Extension()
 			if strings.ToLower(ext) != expectedExt {
 				fmt.Printf("Warning: The provided filename extension '%s' does not match the format '%s'. Expected '%s'.\n",
 					ext, format, expectedExt)
@@ -670,177 +503,27 @@ Examples:
 		}
 	} else {
 		// Generate default filename
-		filename = "technical_debt_record" + formatExtensions[format]
+		filename = "technical_debt_record" + renderer.Extension()
 	}
 
-	// Create an empty technical debt record if the -empty flag is set
-	td := TechnicalDebt{Empty: *emptyPtr}
+	// Create an empty technical debt record if the -empty flag is set; the
+	// custom fields of the repository hold their placeholders
+	td := tdr.TechnicalDebt{Empty: *emptyPtr, CustomFields: schema.Placeholders()}
 
-	// If not generating an empty file, prompt user for inputs
+	// If not generating an empty file, collect the fields from the input file,
+	// the flags and the prompts
 	if !*emptyPtr {
-		var err error
-
-		td.Title, err = getInput("Enter the Title of the Technical Debt: ", true)
-		if err != nil {
-			fmt.Println("Error reading title:", err)
-			return
-		}
-
-		td.Author, err = getInput("Enter the Author of the Document: ", true)
-		if err != nil {
-			fmt.Println("Error reading author:", err)
-			return
-		}
-
-		td.Version, err = getInput("Enter the Version (e.g., 1.0.0): ", true)
+		td, err = collectRecord(fields, *inputPtr, schema)
 		if err != nil {
-			fmt.Println("Error reading version:", err)
-			return
-		}
-
-		// Prompt for Date with default as today
-		dateInput, err := getInput("Enter the Date (YYYY-MM-DD) [Leave blank for today]: ", false)
-		if err != nil {
-			fmt.Println("Error reading date:", err)
-			return
-		}
-		if dateInput == "" {
-			td.Date = time.Now().Format("2006-01-02")
-		} else {
-			// Validate date format
-			_, err := time.Parse("2006-01-02", dateInput)
-			if err != nil {
-				fmt.Println("Invalid date format. Please use YYYY-MM-DD.")
-				return
-			}
-			td.Date = dateInput
-		}
-
-		// Get State
-		td.State, err = getState()
-		if err != nil {
-			fmt.Println("Error reading state:", err)
-			return
-		}
-
-		td.Relations, err = getRelations()
-		if err != nil {
-			fmt.Println("Error reading relations:", err)
-			return
-		}
-
-		// Additional fields
-		td.Summary, err = getInput("Enter Summary: ", false)
-		if err != nil {
-			fmt.Println("Error reading summary:", err)
-			return
-		}
-
-		td.Context, err = getInput("Enter Context: ", false)
-		if err != nil {
-			fmt.Println("Error reading context:", err)
-			return
-		}
-
-		td.ImpactTech, err = getInput("Enter Technical Impact: ", false)
-		if err != nil {
-			fmt.Println("Error reading technical impact:", err)
-			return
-		}
-
-		td.ImpactBus, err = getInput("Enter Business Impact: ", false)
-		if err != nil {
-			fmt.Println("Error reading business impact:", err)
-			return
-		}
-
-		td.Symptoms, err = getInput("Enter Symptoms: ", false)
-		if err != nil {
-			fmt.Println("Error reading symptoms:", err)
-			return
-		}
-
-		td.Severity, err = getInput("Enter Severity (Critical / High / Medium / Low): ", false)
-		if err != nil {
-			fmt.Println("Error reading severity:", err)
-			return
-		}
-
-		td.PotentialRisks, err = getInput("Enter Potential Risks: ", false)
-		if err != nil {
-			fmt.Println("Error reading potential risks:", err)
-			return
-		}
-
-		td.ProposedSol, err = getInput("Enter Proposed Solution: ", false)
-		if err != nil {
-			fmt.Println("Error reading proposed solution:", err)
-			return
-		}
-
-		td.CostDelay, err = getInput("Enter Cost of Delay: ", false)
-		if err != nil {
-			fmt.Println("Error reading cost of delay:", err)
-			return
-		}
-
-		td.Effort, err = getInput("Enter Effort to Resolve: ", false)
-		if err != nil {
-			fmt.Println("Error reading effort:", err)
-			return
-		}
-
-		td.Dependencies, err = getInput("Enter Dependencies: ", false)
-		if err != nil {
-			fmt.Println("Error reading dependencies:", err)
-			return
-		}
-
-		td.Additional, err = getInput("Enter Additional Notes: ", false)
-		if err != nil {
-			fmt.Println("Error reading additional notes:", err)
-			return
-		}
-
-		// Validate inputs
-		if err := validateTechnicalDebt(td); err != nil {
-			fmt.Println("Validation error:", err)
-			return
+			return err
 		}
 	}
 
 	// Generate content based on format
-	switch format {
-	case "markdown":
-		content := generateMarkdown(td)
-		err := os.WriteFile(filename, []byte(content), 0644)
-		if err != nil {
-			fmt.Println("Error generating Markdown file:", err)
-			return
-		}
-	case "ascii":
-		content := generateASCII(td)
-		err := os.WriteFile(filename, []byte(content), 0644)
-		if err != nil {
-			fmt.Println("Error generating ASCII file:", err)
-			return
-		}
-	case "pdf":
-		err := generatePDF(td, filename)
-		if err != nil {
-			fmt.Println("Error generating PDF file:", err)
-			return
-		}
-	case "excel":
-		err := generateExcel(td, filename)
-		if err != nil {
-			fmt.Println("Error generating Excel file:", err)
-			return
-		}
-	default:
-		fmt.Println("Unsupported format.")
-		return
+	if err := writeRecord(renderer, td, filename); err != nil {
+		return fmt.Errorf("could not generate %s file: %w", format, err)
 	}
 
 	fmt.Printf("\nTechnical Debt record has been saved to '%s'.\n", filename)
+	return nil
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runGraph writes the relations between all repository records as a graph
+func runGraph(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	format := fs.String("format", "dot", "Graph format: "+strings.Join(tdr.GraphFormats, ", "))
+	output := fs.String("output", "-", "Output filename, '-' for standard output")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		return tdr.WriteGraph(os.Stdout, *format, records)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := tdr.WriteGraph(file, *format, records); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Graph has been saved to '%s'.\n", *output)
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// importers read records edited outside the repository, by format name
+var importers = []struct {
+	name string
+	read func(r io.Reader, schema *tdr.Schema) ([]tdr.ImportRow, error)
+}{
+	{"excel", tdr.ReadWorkbook},
+	{"csv", func(r io.Reader, _ *tdr.Schema) ([]tdr.ImportRow, error) { return tdr.ReadCSV(r) }},
+}
+
+// runImport creates or updates repository records from the rows of a file
+func runImport(cmd *command, args []string) error {
+	var names []string
+	for _, i := range importers {
+		names = append(names, i.name)
+	}
+	fs := cmd.flagSet()
+	from := fs.String("from", "", "Import format: "+strings.Join(names, ", "))
+	actor := fs.String("actor", "", "Who changes the state, recorded in the history (default: git user.name or the login name)")
+	args = parseFlags(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		return errors.New("import takes exactly one file")
+	}
+
+	index := -1
+	for i, importer := range importers {
+		if strings.EqualFold(importer.name, *from) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("unsupported import format %q, use -from with one of: %s", *from, strings.Join(names, ", "))
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	rows, err := importers[index].read(file, repo.Schema)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	if err := importRecords(repo, rows, *actor); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// importedUpdate is a record changed by an import, together with the relations
+// it had before
+type importedUpdate struct {
+	rec      tdr.Record
+	previous []tdr.Relation
+}
+
+// importRecords creates or updates the records of the rows
+func importRecords(repo *tdr.Repository, rows []tdr.ImportRow, actor string) error {
+	// Check every row before the first record is written, so that a file with
+	// errors leaves the repository unchanged
+	var creates []tdr.TechnicalDebt
+	var updates []importedUpdate
+	var errs []error
+	unchanged := 0
+	seen := make(map[int]int)
+	for _, row := range rows {
+		// A second row for a record would overwrite the changes of the first
+		if number, err := tdr.ParseID(row.ID); err == nil {
+			if first, ok := seen[number]; ok {
+				errs = append(errs, fmt.Errorf("row %d: duplicate row for %s, see row %d", row.Row, tdr.FormatID(number), first))
+				continue
+			}
+			seen[number] = row.Row
+		}
+		if row.ID == "" {
+			td, err := importNew(repo, row)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			creates = append(creates, td)
+			continue
+		}
+		update, changed, err := importUpdate(repo, row, actor)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case changed:
+			updates = append(updates, update)
+		default:
+			unchanged++
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("nothing was imported:\n%w", errors.Join(errs...))
+	}
+
+	// Saving a record mirrors its typed relations on their targets, which may
+	// be updated by later rows. The relations a row added or removed are
+	// therefore applied to the relations the record has when it is saved,
+	// so that the mirrored ones are kept.
+	for _, update := range updates {
+		current, err := repo.Find(update.rec.ID)
+		if err != nil {
+			return err
+		}
+		update.rec.Relations = mergeRelations(current.Relations, update.previous, update.rec.Relations)
+		if err := repo.Save(update.rec); err != nil {
+			return err
+		}
+		fmt.Printf("Technical Debt record %s has been updated.\n", update.rec.ID)
+	}
+	for _, td := range creates {
+		td, path, err := repo.Create(td)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Technical Debt record %s has been saved to '%s'.\n", td.ID, relativePath(path))
+	}
+	fmt.Printf("%d rows imported: %d created, %d updated, %d unchanged.\n", len(rows), len(creates), len(updates), unchanged)
+	return nil
+}
+
+// importNew returns the record a row without ID creates. Like generate, a
+// missing date defaults to today.
+func importNew(repo *tdr.Repository, row tdr.ImportRow) (tdr.TechnicalDebt, error) {
+	var td tdr.TechnicalDebt
+	if err := row.Apply(&td); err != nil {
+		return td, err
+	}
+	if td.Date == "" {
+		td.Date = time.Now().Format(tdr.DateFormat)
+	}
+	if err := repo.Validate(&td); err != nil {
+		return td, fmt.Errorf("row %d: %w", row.Row, err)
+	}
+	if err := repo.ResolveRelations(&td); err != nil {
+		return td, fmt.Errorf("row %d: %w", row.Row, err)
+	}
+	return td, nil
+}
+
+// importUpdate applies a row to the record with its ID and reports whether the
+// record changed. State changes follow the workflow as with edit.
+func importUpdate(repo *tdr.Repository, row tdr.ImportRow, actor string) (importedUpdate, bool, error) {
+	rec, err := repo.Find(row.ID)
+	if err != nil {
+		return importedUpdate{}, false, fmt.Errorf("row %d: %w", row.Row, err)
+	}
+	original := rec.TechnicalDebt
+	update := importedUpdate{rec: rec, previous: original.Relations}
+	if err := row.Apply(&update.rec.TechnicalDebt); err != nil {
+		return update, false, err
+	}
+	td := &update.rec.TechnicalDebt
+	if reflect.DeepEqual(*td, original) {
+		return update, false, nil
+	}
+	if !slices.Equal(td.Relations, original.Relations) {
+		if err := repo.ResolveRelations(td); err != nil {
+			return update, false, fmt.Errorf("row %d: %w", row.Row, err)
+		}
+	}
+	if err := changeState(td, original.State, actor); err != nil {
+		return update, false, fmt.Errorf("row %d: %s: %w", row.Row, rec.ID, err)
+	}
+	if err := repo.Validate(td); err != nil {
+		return update, false, fmt.Errorf("row %d: %s: %w", row.Row, rec.ID, err)
+	}
+	return update, true, nil
+}
+
+// mergeRelations applies the changes from previous to imported to the current
+// relations of a record: relations the import removed are dropped and those it
+// added are appended, while relations added since, such as mirrored ones, stay
+func mergeRelations(current, previous, imported []tdr.Relation) []tdr.Relation {
+	var merged []tdr.Relation
+	for _, rel := range current {
+		if slices.Contains(imported, rel) || !slices.Contains(previous, rel) {
+			merged = append(merged, rel)
+		}
+	}
+	for _, rel := range imported {
+		if !slices.Contains(previous, rel) && !slices.Contains(merged, rel) {
+			merged = append(merged, rel)
+		}
+	}
+	return merged
+}
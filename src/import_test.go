@@ -0,0 +1,97 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// newImportRepository creates a repository holding one valid record per title
+func newImportRepository(t *testing.T, titles ...string) *tdr.Repository {
+	t.Helper()
+	repo, err := tdr.InitRepository(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("InitRepository() error = %v", err)
+	}
+	for _, title := range titles {
+		td := tdr.TechnicalDebt{
+			Title:   title,
+			Author:  "Jane Doe",
+			Version: "1.0.0",
+			Date:    "2024-04-15",
+			State:   "Identified",
+		}
+		if _, _, err := repo.Create(td); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	return repo
+}
+
+// TestImportRelations checks that a relation added by one row is kept when the
+// row of its target updates the target as well
+func TestImportRelations(t *testing.T) {
+	repo := newImportRepository(t, "Outdated Library", "Missing Tests")
+
+	input := "ID,Title,Relations,Summary\n" +
+		"TDR-0001,Outdated Library,blocks TDR-0002,\n" +
+		"TDR-0002,Missing Tests,,Tests are missing\n"
+	rows, err := tdr.ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if err := importRecords(repo, rows, "Jane Doe"); err != nil {
+		t.Fatalf("importRecords() error = %v", err)
+	}
+
+	tests := []struct {
+		id          string
+		wantRelated []tdr.Relation
+		wantSummary string
+	}{
+		{id: "TDR-0001", wantRelated: []tdr.Relation{{Kind: tdr.Blocks, Target: "TDR-0002"}}},
+		{id: "TDR-0002", wantRelated: []tdr.Relation{{Kind: tdr.BlockedBy, Target: "TDR-0001"}}, wantSummary: "Tests are missing"},
+	}
+	for _, tt := range tests {
+		rec, err := repo.Find(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rec.Relations, tt.wantRelated) {
+			t.Errorf("%s Relations = %v, want %v", tt.id, rec.Relations, tt.wantRelated)
+		}
+		if rec.Summary != tt.wantSummary {
+			t.Errorf("%s Summary = %q, want %q", tt.id, rec.Summary, tt.wantSummary)
+		}
+	}
+}
+
+// TestImportDuplicateRows checks that a file with two rows for one record is
+// rejected before any record is written
+func TestImportDuplicateRows(t *testing.T) {
+	repo := newImportRepository(t, "Outdated Library", "Missing Tests")
+
+	input := "ID,Title,Summary\n" +
+		"TDR-0002,Missing Tests,Tests are missing\n" +
+		"TDR-0001,Outdated Library,First\n" +
+		"tdr-1,Outdated Library,Second\n"
+	rows, err := tdr.ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	err = importRecords(repo, rows, "Jane Doe")
+	if err == nil || !strings.Contains(err.Error(), "row 4: duplicate row for TDR-0001, see row 3") {
+		t.Fatalf("importRecords() error = %v, want a duplicate row error", err)
+	}
+	for _, id := range []string{"TDR-0001", "TDR-0002"} {
+		rec, err := repo.Find(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Summary != "" {
+			t.Errorf("%s Summary = %q, want it unchanged", id, rec.Summary)
+		}
+	}
+}
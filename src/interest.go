@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runInterest reports the interest accrued by the repository records
+func runInterest(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	date := fs.String("date", "", "Compute the interest as of this date (YYYY-MM-DD), defaults to today")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	now := time.Now()
+	if *date != "" {
+		var err error
+		if now, err = time.Parse(tdr.DateFormat, *date); err != nil {
+			return fmt.Errorf("invalid date %q, use YYYY-MM-DD", *date)
+		}
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+	report := tdr.AccruedInterest(records, now)
+	if len(report.Records) == 0 {
+		fmt.Println("No records have an interest rate, set one with 'edit ID -interest-rate HOURS'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tSEVERITY\tSINCE\tWEEKS\tHOURS/WEEK\tINTEREST (H)\tTITLE")
+	for _, r := range report.Records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t%s\t%.1f\t%s\n", r.ID, r.State, orDash(r.Severity), r.Date,
+			r.Weeks, tdr.FormatNumber(r.InterestRate), r.Hours, r.Title)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, grouping := range []struct {
+		title  string
+		groups []tdr.InterestGroup
+	}{
+		{"STATE", report.ByState},
+		{"SEVERITY", report.BySeverity},
+	} {
+		fmt.Println()
+		fmt.Fprintf(w, "%s\tRECORDS\tINTEREST (H)\n", grouping.title)
+		for _, group := range grouping.groups {
+			fmt.Fprintf(w, "%s\t%d\t%.1f\n", group.Name, group.Records, group.Hours)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\nTotal interest as of %s: %.1f hours\n", now.Format(tdr.DateFormat), report.Total)
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runLint reports the problems found in the repository records
+func runLint(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	problems, err := repo.Lint()
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(problems))
+	}
+	fmt.Println("No problems found.")
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runPrioritize prints the repository records ranked by WSJF score
+func runPrioritize(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	all := fs.Bool("all", false, "Include resolved, closed and rejected records")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+	var open []tdr.Record
+	for _, rec := range records {
+		if *all || !tdr.Settled(rec.State) {
+			open = append(open, rec)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tID\tWSJF\tCOST OF DELAY/WEEK\tRISK REDUCTION\tEFFORT\tSTATE\tTITLE")
+	for i, p := range tdr.Prioritize(open) {
+		rank, score := "-", "-"
+		if p.Scored {
+			rank, score = fmt.Sprint(i+1), fmt.Sprintf("%.2f", p.Score)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", rank, p.ID, score,
+			orDash(estimate(p.CostOfDelayWeekly)), orDash(estimate(p.RiskReduction)), orDash(estimate(p.EffortEstimate)),
+			p.State, p.Title)
+	}
+	return w.Flush()
+}
+
+// estimate formats an estimate for a table, or returns "" if it is not set
+func estimate(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return tdr.FormatNumber(v)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runReport writes the portfolio view of all repository records
+func runReport(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	format := fs.String("format", "markdown", "Report format: "+strings.Join(tdr.ReportFormats, ", "))
+	output := fs.String("output", "-", "Output filename, '-' for standard output")
+	oldest := fs.Int("oldest", 10, "Number of oldest open records to list")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+	if *oldest < 0 {
+		return fmt.Errorf("invalid -oldest %d, use 0 or more", *oldest)
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+	portfolio := tdr.NewPortfolio(records, time.Now(), *oldest)
+
+	if *output == "-" {
+		return tdr.WriteReport(os.Stdout, *format, portfolio)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := tdr.WriteReport(file, *format, portfolio); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Report has been saved to '%s'.\n", *output)
+	return nil
+}
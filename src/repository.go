@@ -0,0 +1,330 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ms1963/TechnicalDebtRecords/tdr"
+)
+
+// runInit creates a TDR repository in the current directory
+func runInit(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	args = parseFlags(fs, args)
+	if len(args) > 1 {
+		fs.Usage()
+		return errors.New("init takes at most one directory")
+	}
+
+	dir := ""
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	repo, err := tdr.InitRepository(".", dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Initialized TDR repository, records are stored in '%s'.\n", relativePath(repo.Dir))
+	return nil
+}
+
+// runNew creates the next numbered record in the repository
+func runNew(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	input := fs.String("input", "", "Read the record from a JSON or YAML file ('-' for stdin)")
+	fields := newRecordFlags(fs)
+	args = parseFlags(fs, args)
+
+	// As with adr-tools, the remaining arguments form the title
+	if len(args) > 0 {
+		if fields.provided()["title"] {
+			return errors.New("the title was given both as -title and as arguments")
+		}
+		fs.Set("title", strings.Join(args, " "))
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+
+	td, err := collectRecord(fields, *input, repo.Schema)
+	if err != nil {
+		return err
+	}
+
+	td, path, err := repo.Create(td)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nTechnical Debt record %s has been saved to '%s'.\n", td.ID, relativePath(path))
+	return nil
+}
+
+// runList prints a table of the records in the repository
+func runList(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	state := fs.String("state", "", "Only list records in this state")
+	severity := fs.String("severity", "", "Only list records with this severity")
+	args = parseFlags(fs, args)
+	if len(args) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	records, err := repo.Records()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tSEVERITY\tDATE\tTITLE")
+	for _, rec := range records {
+		if *state != "" && !strings.EqualFold(rec.State, *state) {
+			continue
+		}
+		if *severity != "" && !strings.EqualFold(rec.Severity, *severity) {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rec.ID, rec.State, orDash(rec.Severity), rec.Date, rec.Title)
+	}
+	return w.Flush()
+}
+
+// runShow renders a repository record to standard output or a file
+func runShow(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	format := fs.String("format", "markdown", "Output format: "+strings.Join(tdr.Formats(), ", "))
+	output := fs.String("output", "-", "Output filename, '-' for standard output")
+	templateFile := fs.String("template", "", "Render with this Go text/template file (NAME.EXT.tmpl) instead of -format")
+	fonts := newFontFlags(fs)
+	args = parseFlags(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		return errors.New("show takes exactly one record ID")
+	}
+
+	renderer, err := selectRenderer(*format, *templateFile)
+	if err != nil {
+		return err
+	}
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	rec, err := repo.Find(args[0])
+	if err != nil {
+		return err
+	}
+	links, err := repo.Links()
+	if err != nil {
+		return err
+	}
+	renderer = tdr.WithSchema(tdr.WithLinks(renderer, links), repo.Schema)
+	return writeRecord(fonts.apply(renderer), rec.TechnicalDebt, *output)
+}
+
+// runEdit changes a repository record, either from flags or in an editor
+func runEdit(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	fields := newRecordFlags(fs)
+	actor := fs.String("actor", "", "Who changes the state, recorded in the history (default: git user.name or the login name)")
+	args = parseFlags(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		return errors.New("edit takes exactly one record ID")
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	rec, err := repo.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Without field flags the record is edited as a file
+	provided := fields.provided()
+	delete(provided, "actor")
+	if len(provided) == 0 {
+		return editFile(repo, rec, *actor)
+	}
+
+	previous := rec.State
+	if err := fields.apply(&rec.TechnicalDebt); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	if provided["relation"] {
+		if err := repo.ResolveRelations(&rec.TechnicalDebt); err != nil {
+			return err
+		}
+	}
+	if err := changeState(&rec.TechnicalDebt, previous, *actor); err != nil {
+		return err
+	}
+	if err := repo.Save(rec); err != nil {
+		return err
+	}
+	fmt.Printf("Technical Debt record %s has been updated.\n", rec.ID)
+	return nil
+}
+
+// editFile lets the user edit a copy of the record file in their editor and
+// saves it if the result is valid. Invalid edits are kept in the copy.
+func editFile(repo *tdr.Repository, rec tdr.Record, actor string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	original, err := os.ReadFile(rec.Path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", rec.ID+"-*.md")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// The editor setting may contain arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], tmpPath)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w (your changes are in %s)", err, tmpPath)
+	}
+
+	edited, err := tdr.LoadRecord(tmpPath)
+	if err == nil && edited.ID != rec.ID {
+		err = fmt.Errorf("the ID was changed from %s to %q", rec.ID, edited.ID)
+	}
+	if err == nil && !slices.Equal(edited.Relations, rec.Relations) {
+		err = repo.ResolveRelations(&edited.TechnicalDebt)
+	}
+	if err == nil {
+		err = changeState(&edited.TechnicalDebt, rec.State, actor)
+	}
+	if err == nil {
+		edited.Path = rec.Path
+		err = repo.Save(edited)
+	}
+	if err != nil {
+		return fmt.Errorf("the edited record was not saved: %w (your changes are in %s)", err, tmpPath)
+	}
+
+	os.Remove(tmpPath)
+	fmt.Printf("Technical Debt record %s has been updated.\n", rec.ID)
+	return nil
+}
+
+// runTransition moves a repository record to another state
+func runTransition(cmd *command, args []string) error {
+	fs := cmd.flagSet()
+	actor := fs.String("actor", "", "Who changes the state, recorded in the history (default: git user.name or the login name)")
+	date := fs.String("date", "", "Date of the transition (YYYY-MM-DD), defaults to today")
+	args = parseFlags(fs, args)
+	if len(args) < 2 {
+		fs.Usage()
+		return errors.New("transition takes a record ID and a state")
+	}
+
+	when := time.Now()
+	if *date != "" {
+		var err error
+		if when, err = time.Parse(tdr.DateFormat, *date); err != nil {
+			return fmt.Errorf("invalid date %q, use YYYY-MM-DD", *date)
+		}
+	}
+
+	repo, err := tdr.OpenRepository(".")
+	if err != nil {
+		return err
+	}
+	rec, err := repo.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Unquoted states such as In Progress arrive as several arguments
+	from := rec.State
+	if err := rec.ChangeState(strings.Join(args[1:], " "), actorOrDefault(*actor), when); err != nil {
+		return fmt.Errorf("%s: %w", rec.ID, err)
+	}
+	if err := repo.Save(rec); err != nil {
+		return err
+	}
+	fmt.Printf("Technical Debt record %s moved from %s to %s.\n", rec.ID, from, rec.State)
+	return nil
+}
+
+// changeState checks a state change made by editing a record. If the state
+// differs from previous, the record is moved through ChangeState so that the
+// state machine is enforced and the transition is added to the history.
+func changeState(td *tdr.TechnicalDebt, previous, actor string) error {
+	if td.State == previous {
+		return nil
+	}
+	target := td.State
+	td.State = previous
+	return td.ChangeState(target, actorOrDefault(actor), time.Now())
+}
+
+// actorOrDefault returns actor, or the git user name or login name if it is empty
+func actorOrDefault(actor string) string {
+	if actor != "" {
+		return actor
+	}
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// orDash returns value, or "-" if it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// relativePath returns path relative to the working directory where possible
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
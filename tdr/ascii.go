@@ -0,0 +1,246 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ASCIIRenderer renders a record as plain text
+type ASCIIRenderer struct{}
+
+// Name implements Renderer
+func (ASCIIRenderer) Name() string { return "ascii" }
+
+// Extension implements Renderer
+func (ASCIIRenderer) Extension() string { return ".txt" }
+
+// Render implements Renderer
+func (ASCIIRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	_, err := io.WriteString(w, GenerateASCII(td))
+	return err
+}
+
+// GenerateASCII generates the Plain ASCII content
+func GenerateASCII(td TechnicalDebt) string {
+	relationsFormatted := asciiRelations(td.Relations)
+
+	if td.Empty {
+		return fmt.Sprintf(`Technical Debt Record
+====================
+    
+Title:
+------
+[Enter Title Here]
+    
+Author:
+-------
+[Enter Author Here]
+    
+Version:
+--------
+[Enter Version Here]
+    
+Date:
+-----
+[Enter Date Here]
+    
+State:
+------
+[Enter State Here]
+    
+Relations:
+----------
+%s
+    
+Summary:
+--------
+*A brief overview of the technical debt, explaining the problem in one or two sentences.*
+    
+Context:
+--------
+*Provide the historical context and reasons why this technical debt exists.*
+    
+Impact:
+-------
+Technical Impact:
+- *Describe the technical impact.*
+    
+Business Impact:
+- *Describe the business impact.*
+    
+Symptoms:
+---------
+*List specific signs that indicate the presence of technical debt.*
+    
+Severity:
+---------
+*[Enter Severity Here: Critical / High / Medium / Low]*
+    
+Potential Risks:
+----------------
+*Potential security vulnerabilities leading to data breaches.*
+    
+Proposed Solution:
+-------------------
+*Describe how to resolve the technical debt.*
+    
+Cost of Delay:
+---------------
+*Explain the consequences of delaying the resolution of the technical debt.*
+    
+Effort to Resolve:
+-------------------
+*Estimate the time, resources, and effort needed to address the debt.*
+    
+Dependencies:
+-------------
+*List any dependencies or blockers that need to be resolved before tackling the debt.*
+    
+Additional Notes:
+-----------------
+*Any other relevant information or considerations.*
+`, relationsFormatted) + asciiFields(td.CustomFields, true)
+	}
+
+	// The ID section is only written for records that belong to a repository
+	idSection := ""
+	if td.ID != "" {
+		idSection = fmt.Sprintf("ID:\n---\n%s\n    \n", td.ID)
+	}
+
+	// Normal ASCII generation
+	return fmt.Sprintf(`Technical Debt Record
+====================
+    
+%sTitle:
+------
+%s
+    
+Author:
+-------
+%s
+    
+Version:
+--------
+%s
+    
+Date:
+-----
+%s
+    
+State:
+------
+%s
+    
+Relations:
+----------
+%s
+    
+Summary:
+--------
+%s
+    
+Context:
+--------
+%s
+    
+Impact:
+-------
+Technical Impact:
+- %s
+    
+Business Impact:
+- %s
+    
+Symptoms:
+---------
+%s
+    
+Severity:
+---------
+%s
+    
+Potential Risks:
+----------------
+%s
+    
+Proposed Solution:
+-------------------
+%s
+    
+Cost of Delay:
+---------------
+%s
+    
+Effort to Resolve:
+-------------------
+%s
+    
+%sDependencies:
+-------------
+%s
+    
+Additional Notes:
+-----------------
+%s
+`, idSection, td.Title, td.Author, td.Version, td.Date, td.State, relationsFormatted, td.Summary, td.Context,
+		td.ImpactTech, td.ImpactBus, td.Symptoms, td.Severity, td.PotentialRisks, td.ProposedSol,
+		td.CostDelay, td.Effort, asciiEstimates(td), td.Dependencies, td.Additional) +
+		asciiFields(td.CustomFields, false) + asciiHistory(td.History)
+}
+
+// asciiRelations renders the relations as lists grouped by kind, untyped relations first
+func asciiRelations(relations []Relation) string {
+	if len(relations) == 0 {
+		return "None"
+	}
+	var lines []string
+	for _, group := range groupRelations(relations) {
+		if group.Kind != RelatesTo {
+			lines = append(lines, group.Kind.Label()+":")
+		}
+		for _, rel := range group.Relations {
+			lines = append(lines, fmt.Sprintf("- %s", rel.Target))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// asciiEstimates renders the estimates that are set as ASCII sections
+func asciiEstimates(td TechnicalDebt) string {
+	var b strings.Builder
+	for _, e := range estimates(&td) {
+		if *e.Value != 0 {
+			fmt.Fprintf(&b, "%s:\n%s\n%s\n    \n", e.Heading, strings.Repeat("-", len(e.Heading)+1), FormatNumber(*e.Value))
+		}
+	}
+	return b.String()
+}
+
+// asciiFields renders the custom fields as ASCII sections; the placeholders
+// of empty templates are marked like the other placeholders
+func asciiFields(fields Fields, placeholder bool) string {
+	var b strings.Builder
+	for _, field := range fields {
+		value := field.Value
+		if placeholder && value != "" {
+			value = "*" + value + "*"
+		}
+		fmt.Fprintf(&b, "    \n%s:\n%s\n%s\n", field.Name, strings.Repeat("-", len(field.Name)+1), value)
+	}
+	return b.String()
+}
+
+// asciiHistory renders the state history, or nothing if there is no history
+func asciiHistory(history []Transition) string {
+	if len(history) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("    \nHistory:\n--------\n")
+	for _, t := range history {
+		fmt.Fprintf(&b, "%s  %s -> %s  (%s)\n", t.Date, t.From, t.To, t.Actor)
+	}
+	return b.String()
+}
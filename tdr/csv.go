@@ -0,0 +1,100 @@
+package tdr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// CSVRenderer renders a record as a CSV table with a header row and the
+// columns of the Excel export, for spreadsheets other than Excel
+type CSVRenderer struct {
+	// Schema declares the custom fields, which get a column each
+	Schema *Schema
+}
+
+// Name implements Renderer
+func (CSVRenderer) Name() string { return "csv" }
+
+// Extension implements Renderer
+func (CSVRenderer) Extension() string { return ".csv" }
+
+// Render implements Renderer. Empty templates consist of the header row only.
+func (r CSVRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	custom := customColumns([]TechnicalDebt{td}, r.Schema)
+	var tds []TechnicalDebt
+	if !td.Empty {
+		tds = append(tds, td)
+	}
+	return writeCSV(w, tds, custom, r.Schema)
+}
+
+// WriteCSV writes all records as rows of one CSV table. The custom fields of
+// the schema, which may be nil, get columns of their own.
+func WriteCSV(w io.Writer, records []Record, schema *Schema) error {
+	tds := make([]TechnicalDebt, len(records))
+	for i, rec := range records {
+		tds[i] = rec.TechnicalDebt
+	}
+	return writeCSV(w, tds, customColumns(tds, schema), schema)
+}
+
+// writeCSV writes the header row and one row per record, with the custom
+// fields in the given columns. Fields containing commas, quotes or line
+// breaks are quoted as described in RFC 4180.
+func writeCSV(w io.Writer, tds []TechnicalDebt, custom []string, schema *Schema) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	if err := cw.Write(append(slices.Clone(excelHeaders), custom...)); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	for _, td := range tds {
+		if err := cw.Write(csvRow(td, custom, schema)); err != nil {
+			return fmt.Errorf("error writing CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	return nil
+}
+
+// csvRow returns the cells of excelRow as text
+func csvRow(td TechnicalDebt, custom []string, schema *Schema) []string {
+	values := excelRow(td, custom, schema)
+	row := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			row[i] = v.Format(DateFormat)
+		case float64:
+			row[i] = FormatNumber(v)
+		default:
+			row[i] = fmt.Sprint(v)
+		}
+	}
+	return row
+}
+
+// ReadCSV reads the records from a CSV table as written by WriteCSV. Quoted
+// fields may span several lines, and a leading byte order mark, as written by
+// some spreadsheets, is ignored.
+func ReadCSV(r io.Reader) ([]ImportRow, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	// Spreadsheets may drop trailing empty cells
+	cr.FieldsPerRecord = -1
+	table, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	return importRows(table)
+}
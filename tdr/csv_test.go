@@ -0,0 +1,67 @@
+package tdr
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCSVRoundTrip checks that multi-line and quoted fields survive a round trip
+func TestCSVRoundTrip(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{Title: "Outdated Library", Author: "Jane Doe", Version: "1.0", Date: "2024-03-01",
+			State: "Identified", Relations: []Relation{{Target: "TDR-0002"}, {Kind: Blocks, Target: "TDR-0003"}},
+			Summary: "First line, with a comma\nSecond \"quoted\" line", Severity: "High", EffortEstimate: 2.5}},
+		{TechnicalDebt: TechnicalDebt{Title: "Slow Build", Author: "Max Mustermann", Version: "2.0", Date: "2024-04-15", State: "Closed"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, records, nil); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if want := strings.Join(excelHeaders, ",") + "\r\n"; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("CSV starts with %q, want the Excel headers", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+	if !strings.Contains(buf.String(), `"First line, with a comma`+"\r\n"+`Second ""quoted"" line"`) {
+		t.Errorf("multi-line field not quoted:\n%s", buf.String())
+	}
+
+	rows, err := ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if len(rows) != len(records) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(records))
+	}
+	for i, row := range rows {
+		var td TechnicalDebt
+		if err := row.Apply(&td); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if !reflect.DeepEqual(td, records[i].TechnicalDebt) {
+			t.Errorf("row %d = %+v, want %+v", row.Row, td, records[i].TechnicalDebt)
+		}
+	}
+}
+
+// TestReadCSVByteOrderMark checks that CSV files saved with a byte order mark are read
+func TestReadCSVByteOrderMark(t *testing.T) {
+	rows, err := ReadCSV(strings.NewReader("\xef\xbb\xbfID,Title\nTDR-0001,Outdated Library\n"))
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].ID != "TDR-0001" {
+		t.Errorf("rows = %+v", rows)
+	}
+}
+
+// TestCSVRendererEmpty checks that an empty template is the header row only
+func TestCSVRendererEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (CSVRenderer{}).Render(&buf, TechnicalDebt{Empty: true}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "\r\n"); got != 1 {
+		t.Errorf("empty template has %d lines, want 1:\n%s", got, buf.String())
+	}
+}
@@ -0,0 +1,218 @@
+package tdr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeJSON reads a record from a JSON object, rejecting unknown keys at any
+// level. The schema_version key is optional; documents without it are read as
+// the current schema version.
+func DecodeJSON(r io.Reader) (TechnicalDebt, error) {
+	var td TechnicalDebt
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return td, fmt.Errorf("error reading JSON: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return td, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := checkRecordKeys(mapKeys(raw), "json"); err != nil {
+		return td, err
+	}
+
+	// Keys of nested objects such as the history entries are checked as well
+	var doc Document
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return td, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := checkSchemaVersion(doc.SchemaVersion); err != nil {
+		return td, err
+	}
+	td = doc.TechnicalDebt
+	trimFields(&td)
+	normalizeSeverity(&td)
+	return td, nil
+}
+
+// DecodeYAML reads a record from a YAML mapping, rejecting unknown keys. The
+// schema_version key is optional as for DecodeJSON.
+func DecodeYAML(r io.Reader) (TechnicalDebt, error) {
+	var td TechnicalDebt
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return td, fmt.Errorf("error reading YAML: %w", err)
+	}
+
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return td, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if err := checkRecordKeys(mapKeys(raw), "yaml"); err != nil {
+		return td, err
+	}
+
+	var doc Document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return td, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if err := checkSchemaVersion(doc.SchemaVersion); err != nil {
+		return td, err
+	}
+	td = doc.TechnicalDebt
+	trimFields(&td)
+	normalizeSeverity(&td)
+	return td, nil
+}
+
+// Decode reads a record from JSON or YAML. The format is taken from the
+// extension of name (".json", ".yaml" or ".yml"); without a known extension
+// the content is inspected and treated as JSON if it starts with "{".
+func Decode(r io.Reader, name string) (TechnicalDebt, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return DecodeJSON(r)
+	case ".yaml", ".yml":
+		return DecodeYAML(r)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return TechnicalDebt{}, fmt.Errorf("error reading input: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return DecodeJSON(bytes.NewReader(data))
+	}
+	return DecodeYAML(bytes.NewReader(data))
+}
+
+// ReadFile reads a record from a Markdown, JSON or YAML file, chosen by its extension
+func ReadFile(path string) (TechnicalDebt, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return TechnicalDebt{}, err
+	}
+	defer file.Close()
+
+	var td TechnicalDebt
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		td, err = ParseMarkdown(file)
+	case ".json", ".yaml", ".yml":
+		td, err = Decode(file, path)
+	default:
+		return td, fmt.Errorf("%s: unsupported record file, expected .md, .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return td, fmt.Errorf("%s: %w", path, err)
+	}
+	return td, nil
+}
+
+// recordKeys returns the key names declared by the given struct tag of TechnicalDebt
+func recordKeys(tag string) []string {
+	var keys []string
+	t := reflect.TypeOf(TechnicalDebt{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// recordKeyKind returns the kind of the field holding the record key. Custom
+// fields are written as a mapping and reported as reflect.Map.
+func recordKeyKind(key string) reflect.Kind {
+	t := reflect.TypeOf(TechnicalDebt{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == key {
+			if t.Field(i).Type == reflect.TypeOf(Fields{}) {
+				return reflect.Map
+			}
+			return t.Field(i).Type.Kind()
+		}
+	}
+	return reflect.Invalid
+}
+
+// isListKey reports whether the record key holds a list, such as relations or history
+func isListKey(key string) bool {
+	return recordKeyKind(key) == reflect.Slice
+}
+
+// isFieldsKey reports whether the record key holds the custom fields
+func isFieldsKey(key string) bool {
+	return recordKeyKind(key) == reflect.Map
+}
+
+// isNumberKey reports whether the record key holds a number, such as effort_estimate
+func isNumberKey(key string) bool {
+	return recordKeyKind(key) == reflect.Float64
+}
+
+// checkRecordKeys returns an error naming the first key that is not a record field
+func checkRecordKeys(keys []string, tag string) error {
+	allowed := map[string]bool{"schema_version": true}
+	for _, key := range recordKeys(tag) {
+		allowed[key] = true
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !allowed[key] {
+			return fmt.Errorf("unknown key %q, allowed keys are: %s", key, strings.Join(recordKeys(tag), ", "))
+		}
+	}
+	return nil
+}
+
+// trimFields removes surrounding whitespace, such as the trailing newline of a
+// YAML block scalar, from all text fields and custom fields
+func trimFields(td *TechnicalDebt) {
+	v := reflect.ValueOf(td).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Field(i); field.Kind() == reflect.String {
+			field.SetString(strings.TrimSpace(field.String()))
+		}
+	}
+	for i := range td.CustomFields {
+		td.CustomFields[i].Value = strings.TrimSpace(td.CustomFields[i].Value)
+	}
+}
+
+// normalizeSeverity rewrites a severity in any case, e.g. "HIGH", in its
+// canonical form. Unknown severities are kept for Validate to report.
+func normalizeSeverity(td *TechnicalDebt) {
+	if severity, err := ParseSeverity(td.Severity); err == nil {
+		td.Severity = severity
+	}
+}
+
+// mapKeys returns the keys of m
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
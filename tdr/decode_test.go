@@ -0,0 +1,143 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDecode checks that JSON and YAML records map onto TechnicalDebt
+func TestDecode(t *testing.T) {
+	want := TechnicalDebt{
+		Title:      "Outdated Library",
+		Author:     "Jane Doe",
+		Version:    "1.0.0",
+		Date:       "2024-04-15",
+		State:      "Analyzed",
+		Relations:  []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
+		Summary:    "The library is outdated\nand causes security vulnerabilities.",
+		ImpactTech: "Security risks and maintainability issues.",
+		Severity:   "High",
+	}
+
+	tests := []struct {
+		name  string
+		file  string
+		input string
+	}{
+		{
+			name: "JSON",
+			file: "debt.json",
+			input: `{
+  "title": "Outdated Library",
+  "author": "Jane Doe",
+  "version": "1.0.0",
+  "date": "2024-04-15",
+  "state": "Analyzed",
+  "relations": ["TDR-102", "TDR-103"],
+  "summary": "The library is outdated\nand causes security vulnerabilities.",
+  "technical_impact": "Security risks and maintainability issues.",
+  "severity": "High"
+}`,
+		},
+		{
+			name: "YAML",
+			file: "debt.yaml",
+			input: `title: Outdated Library
+author: Jane Doe
+version: 1.0.0
+date: 2024-04-15
+state: Analyzed
+relations:
+  - TDR-102
+  - TDR-103
+summary: |
+  The library is outdated
+  and causes security vulnerabilities.
+technical_impact: Security risks and maintainability issues.
+severity: High
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, name := range []string{tt.file, ""} {
+			t.Run(tt.name+" "+name, func(t *testing.T) {
+				got, err := Decode(strings.NewReader(tt.input), name)
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Decode() = %+v, want %+v", got, want)
+				}
+			})
+		}
+	}
+}
+
+// TestDecodeUnknownKey checks that keys which are not record fields are
+// rejected, also inside history entries
+func TestDecodeUnknownKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		input string
+		want  string
+	}{
+		{name: "JSON", file: "debt.json", input: `{"title": "Outdated Library", "owner": "Jane Doe"}`, want: `unknown key "owner"`},
+		{name: "YAML", file: "debt.yml", input: "title: Outdated Library\nowner: Jane Doe\n", want: `unknown key "owner"`},
+		{
+			name:  "JSON history",
+			file:  "debt.json",
+			input: `{"title": "Outdated Library", "history": [{"date": "2024-04-15", "from": "Identified", "to": "Analyzed", "acter": "Jane Doe"}]}`,
+			want:  `"acter"`,
+		},
+		{
+			name:  "YAML history",
+			file:  "debt.yml",
+			input: "title: Outdated Library\nhistory:\n  - date: 2024-04-15\n    from: Identified\n    to: Analyzed\n    acter: Jane Doe\n",
+			want:  "acter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.input), tt.file)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalizeSeverity checks that severities are imported regardless of case
+// and unknown ones are kept for validation
+func TestNormalizeSeverity(t *testing.T) {
+	tests := []struct {
+		file  string
+		input string
+		want  string
+	}{
+		{"debt.json", `{"severity": "HIGH"}`, "High"},
+		{"debt.yaml", "severity: critical\n", "Critical"},
+		{"debt.yaml", "severity: urgent\n", "urgent"},
+		{"debt.md", "# Technical Debt Record\n\n## Severity\n\nmedium\n", "Medium"},
+	}
+
+	for _, tt := range tests {
+		var td TechnicalDebt
+		var err error
+		if strings.HasSuffix(tt.file, ".md") {
+			td, err = ParseMarkdown(strings.NewReader(tt.input))
+		} else {
+			td, err = Decode(strings.NewReader(tt.input), tt.file)
+		}
+		if err != nil {
+			t.Errorf("%s: error = %v", tt.input, err)
+			continue
+		}
+		if td.Severity != tt.want {
+			t.Errorf("%s: Severity = %q, want %q", tt.input, td.Severity, tt.want)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelSheet is the sheet holding the records, one per row
+const excelSheet = "TechnicalDebt"
+
+// excelSummarySheet is the sheet holding the counts by state and severity
+const excelSummarySheet = "Summary"
+
+// excelHeaders are the column headers of the built-in fields, in the order of
+// excelRow; the columns of the custom fields follow them
+var excelHeaders = []string{
+	"ID",
+	"Title",
+	"Author",
+	"Version",
+	"Date",
+	"State",
+	"Relations",
+	"Summary",
+	"Context",
+	"Technical Impact",
+	"Business Impact",
+	"Symptoms",
+	"Severity",
+	"Potential Risks",
+	"Proposed Solution",
+	"Cost of Delay",
+	"Effort to Resolve",
+	"Effort Estimate",
+	"Cost of Delay per Week",
+	"Risk Reduction",
+	"Interest Rate (Hours per Week)",
+	"Dependencies",
+	"Additional Notes",
+}
+
+// excelDescriptions are the field descriptions shown as comments on the header
+// cells; the sections of the record use the placeholders of empty templates
+var excelDescriptions = map[string]string{
+	"ID":                             "The repository ID of the record, e.g. TDR-0001. Leave empty for new records.",
+	"Title":                          "A short, descriptive title of the technical debt.",
+	"Author":                         "The person or team who recorded the technical debt.",
+	"Version":                        "The version of the system or record the debt applies to.",
+	"Date":                           "The date the debt was recorded, as YYYY-MM-DD.",
+	"State":                          "The state of the record: " + strings.Join(AllowedStates, ", ") + ".",
+	"Relations":                      "Comma-separated related records or ADRs, optionally preceded by the relation kind, e.g. blocks TDR-0002.",
+	"Severity":                       "The severity of the debt: " + strings.Join(AllowedSeverities, ", ") + ".",
+	"Effort Estimate":                "The effort to resolve the debt as a number, e.g. in person-days.",
+	"Cost of Delay per Week":         "The cost of delaying the resolution by one week as a number.",
+	"Risk Reduction":                 "The risk reduced by resolving the debt as a number.",
+	"Interest Rate (Hours per Week)": "The hours lost to the debt every week it is not resolved.",
+}
+
+// excelValidationRows is the number of rows below the header that get the
+// validations, so that rows added in Excel are validated as well
+const excelValidationRows = 1000
+
+// excelDateFormat displays dates like DateFormat
+const excelDateFormat = "yyyy-mm-dd"
+
+// ExcelRenderer renders a record as an Excel workbook using the excelize library
+type ExcelRenderer struct {
+	// Schema declares the types, allowed values and descriptions of the custom fields
+	Schema *Schema
+}
+
+// Name implements Renderer
+func (ExcelRenderer) Name() string { return "excel" }
+
+// Extension implements Renderer
+func (ExcelRenderer) Extension() string { return ".xlsx" }
+
+// Render implements Renderer
+func (r ExcelRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Reuse the default sheet, so that no empty "Sheet1" is left behind
+	if err := f.SetSheetName(f.GetSheetName(0), excelSheet); err != nil {
+		return fmt.Errorf("error creating Excel sheet: %w", err)
+	}
+	if err := writeRecordSheet(f, []TechnicalDebt{td}, r.Schema); err != nil {
+		return err
+	}
+
+	// Write the Excel workbook
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("error writing Excel workbook: %w", err)
+	}
+	return nil
+}
+
+// WriteWorkbook writes all records as rows of one table, with a frozen header
+// row and an autofilter, and adds a summary sheet counting the records by
+// state and severity. The custom fields of the schema, which may be nil, get
+// columns of their own.
+func WriteWorkbook(w io.Writer, records []Record, schema *Schema) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// The summary comes first and takes the place of the default sheet
+	if err := f.SetSheetName(f.GetSheetName(0), excelSummarySheet); err != nil {
+		return fmt.Errorf("error creating Excel sheet: %w", err)
+	}
+	if _, err := f.NewSheet(excelSheet); err != nil {
+		return fmt.Errorf("error creating Excel sheet: %w", err)
+	}
+
+	tds := make([]TechnicalDebt, len(records))
+	for i, rec := range records {
+		tds[i] = rec.TechnicalDebt
+	}
+	if err := writeRecordSheet(f, tds, schema); err != nil {
+		return err
+	}
+	if err := writeSummarySheet(f, tds); err != nil {
+		return err
+	}
+	f.SetActiveSheet(0)
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("error writing Excel workbook: %w", err)
+	}
+	return nil
+}
+
+// ReadWorkbook reads the records from the record sheet of a workbook written by
+// WriteWorkbook or the excel renderer, or from the first sheet if there is no
+// record sheet. Dates, including the date fields of the schema, which may be
+// nil, may be entered as Excel dates or as YYYY-MM-DD text.
+func ReadWorkbook(r io.Reader, schema *Schema) ([]ImportRow, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Excel workbook: %w", err)
+	}
+	defer f.Close()
+
+	sheet := excelSheet
+	if index, _ := f.GetSheetIndex(sheet); index < 0 {
+		sheet = f.GetSheetName(0)
+	}
+	// Raw values keep dates independent of the display format of the cell
+	table, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("error reading Excel sheet %q: %w", sheet, err)
+	}
+	if len(table) > 0 {
+		for i, header := range table[0] {
+			header = strings.TrimSpace(header)
+			spec := schema.Field(header)
+			if !strings.EqualFold(header, "Date") && (spec == nil || spec.Type != FieldDate) {
+				continue
+			}
+			for _, row := range table[1:] {
+				if i < len(row) {
+					row[i] = excelDateText(row[i])
+				}
+			}
+		}
+	}
+	rows, err := importRows(table)
+	if err != nil {
+		return nil, fmt.Errorf("sheet %q: %w", sheet, err)
+	}
+	return rows, nil
+}
+
+// excelDateText returns a date cell as DateFormat text; other values, such as
+// dates entered as text, are returned unchanged
+func excelDateText(value string) string {
+	serial, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value
+	}
+	date, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return value
+	}
+	return date.Format(DateFormat)
+}
+
+// writeRecordSheet fills the record sheet with a bold, frozen header row and
+// one row per record, and enables the autofilter on the table. The header
+// cells describe their field in a comment and the State, Severity and Date
+// columns, and the custom fields with allowed values or dates, only accept
+// valid values.
+func writeRecordSheet(f *excelize.File, tds []TechnicalDebt, schema *Schema) error {
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("error creating Excel style: %w", err)
+	}
+	custom := customColumns(tds, schema)
+	headers := append(slices.Clone(excelHeaders), custom...)
+	if err := f.SetSheetRow(excelSheet, "A1", &headers); err != nil {
+		return fmt.Errorf("error writing Excel header: %w", err)
+	}
+	if err := f.SetRowStyle(excelSheet, 1, 1, bold); err != nil {
+		return fmt.Errorf("error writing Excel header: %w", err)
+	}
+
+	for i, td := range tds {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		row := excelRow(td, custom, schema)
+		if err := f.SetSheetRow(excelSheet, cell, &row); err != nil {
+			return fmt.Errorf("error writing Excel row: %w", err)
+		}
+	}
+
+	lastColumn, _ := excelize.ColumnNumberToName(len(headers))
+	if err := f.SetColWidth(excelSheet, "A", lastColumn, 20); err != nil {
+		return fmt.Errorf("error formatting Excel sheet: %w", err)
+	}
+	if err := f.SetPanes(excelSheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("error freezing Excel header: %w", err)
+	}
+	lastCell, _ := excelize.CoordinatesToCellName(len(headers), len(tds)+1)
+	if err := f.AutoFilter(excelSheet, "A1:"+lastCell, nil); err != nil {
+		return fmt.Errorf("error adding Excel autofilter: %w", err)
+	}
+	if err := addExcelComments(f, headers, schema); err != nil {
+		return err
+	}
+	return addExcelValidations(f, headers, schema, max(len(tds), excelValidationRows)+1)
+}
+
+// customColumns returns the names of the custom fields of the schema and the
+// records, in the order of the schema followed by the order they appear in
+func customColumns(tds []TechnicalDebt, schema *Schema) []string {
+	var columns []string
+	if schema != nil {
+		for _, spec := range schema.Fields {
+			columns = append(columns, spec.Name)
+		}
+	}
+	for _, td := range tds {
+		for _, field := range td.CustomFields {
+			if schema.Field(field.Name) == nil && !slices.Contains(columns, field.Name) {
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+	return columns
+}
+
+// addExcelComments adds the field descriptions to the header cells
+func addExcelComments(f *excelize.File, headers []string, schema *Schema) error {
+	for i, header := range headers {
+		text, ok := excelDescriptions[header]
+		if !ok {
+			text = htmlPlaceholders[header]
+		}
+		if spec := schema.Field(header); spec != nil {
+			text = spec.Description()
+		}
+		if text == "" {
+			continue
+		}
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := f.AddComment(excelSheet, excelize.Comment{
+			Author: "TDR",
+			Cell:   cell,
+			Text:   text,
+		}); err != nil {
+			return fmt.Errorf("error adding Excel comment: %w", err)
+		}
+	}
+	return nil
+}
+
+// addExcelValidations restricts State, Severity and the custom fields with
+// allowed values to those values and Date and the custom date fields to dates,
+// down to lastRow, and formats the dates like DateFormat
+func addExcelValidations(f *excelize.File, headers []string, schema *Schema, lastRow int) error {
+	column := func(header string) string {
+		name, _ := excelize.ColumnNumberToName(slices.Index(headers, header) + 1)
+		return name
+	}
+	span := func(header string) string {
+		return fmt.Sprintf("%[1]s2:%[1]s%[2]d", column(header), lastRow)
+	}
+
+	type list struct {
+		header string
+		values []string
+	}
+	lists := []list{
+		{"State", AllowedStates},
+		{"Severity", AllowedSeverities},
+	}
+	dates := []string{"Date"}
+	for _, header := range headers[len(excelHeaders):] {
+		spec := schema.Field(header)
+		switch {
+		case spec == nil:
+		case len(spec.Values) > 0:
+			lists = append(lists, list{header, spec.Values})
+		case spec.Type == FieldDate:
+			dates = append(dates, header)
+		}
+	}
+
+	for _, list := range lists {
+		dv := excelize.NewDataValidation(true)
+		dv.SetSqref(span(list.header))
+		if err := dv.SetDropList(list.values); err != nil {
+			return fmt.Errorf("error adding Excel validation: %w", err)
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid "+list.header,
+			"Select one of: "+strings.Join(list.values, ", "))
+		if err := f.AddDataValidation(excelSheet, dv); err != nil {
+			return fmt.Errorf("error adding Excel validation: %w", err)
+		}
+	}
+
+	dateFormat := excelDateFormat
+	style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return fmt.Errorf("error creating Excel style: %w", err)
+	}
+	for _, header := range dates {
+		// Excel stores dates as serial numbers, 1 being 1900-01-01 and 2958465 9999-12-31
+		dv := excelize.NewDataValidation(true)
+		dv.SetSqref(span(header))
+		if err := dv.SetRange(1, 2958465, excelize.DataValidationTypeDate, excelize.DataValidationOperatorBetween); err != nil {
+			return fmt.Errorf("error adding Excel validation: %w", err)
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid "+header, "Enter a date as YYYY-MM-DD.")
+		if err := f.AddDataValidation(excelSheet, dv); err != nil {
+			return fmt.Errorf("error adding Excel validation: %w", err)
+		}
+
+		date := column(header)
+		if err := f.SetCellStyle(excelSheet, date+"2", fmt.Sprintf("%s%d", date, lastRow), style); err != nil {
+			return fmt.Errorf("error formatting Excel dates: %w", err)
+		}
+	}
+	return nil
+}
+
+// excelRow returns the cell values of a record in the order of excelHeaders,
+// followed by the custom fields in the given columns; dates are written as
+// dates, estimates as numbers left blank when unset. Custom fields are typed
+// as declared by the schema.
+func excelRow(td TechnicalDebt, custom []string, schema *Schema) []any {
+	row := []any{
+		td.ID,
+		td.Title,
+		td.Author,
+		td.Version,
+		excelDate(td.Date),
+		td.State,
+		joinRelations(td.Relations),
+		td.Summary,
+		td.Context,
+		td.ImpactTech,
+		td.ImpactBus,
+		td.Symptoms,
+		td.Severity,
+		td.PotentialRisks,
+		td.ProposedSol,
+		td.CostDelay,
+		td.Effort,
+		excelNumber(td.EffortEstimate),
+		excelNumber(td.CostOfDelayWeekly),
+		excelNumber(td.RiskReduction),
+		excelNumber(td.InterestRate),
+		td.Dependencies,
+		td.Additional,
+	}
+	for _, name := range custom {
+		value := td.CustomFields.Get(name)
+		if td.Empty {
+			// Empty templates hold placeholders, which are no valid values
+			value = ""
+		}
+		var cell any = value
+		if spec := schema.Field(name); spec != nil && len(spec.Values) == 0 {
+			switch spec.Type {
+			case FieldDate:
+				cell = excelDate(value)
+			case FieldNumber:
+				if n, err := strconv.ParseFloat(value, 64); err == nil {
+					cell = n
+				}
+			}
+		}
+		row = append(row, cell)
+	}
+	return row
+}
+
+// excelDate returns the date as a date cell, so that it passes the date
+// validation, and keeps text that is not a valid date as is
+func excelDate(value string) any {
+	if date, err := time.Parse(DateFormat, value); err == nil {
+		return date
+	}
+	return value
+}
+
+// excelNumber returns v as a cell value, or an empty cell for zero
+func excelNumber(v float64) any {
+	if v == 0 {
+		return ""
+	}
+	return v
+}
+
+// writeSummarySheet writes the summaryRows pivot table with bold header and
+// total rows
+func writeSummarySheet(f *excelize.File, tds []TechnicalDebt) error {
+	rows := summaryRows(tds)
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(excelSummarySheet, cell, &row); err != nil {
+			return fmt.Errorf("error writing Excel summary: %w", err)
+		}
+	}
+
+	// Bold header and total rows
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("error creating Excel style: %w", err)
+	}
+	for _, row := range []int{1, len(rows)} {
+		if err := f.SetRowStyle(excelSummarySheet, row, row, bold); err != nil {
+			return fmt.Errorf("error formatting Excel summary: %w", err)
+		}
+	}
+	if err := f.SetColWidth(excelSummarySheet, "A", "A", 14); err != nil {
+		return fmt.Errorf("error formatting Excel summary: %w", err)
+	}
+	return nil
+}
+
+// summaryRows returns a pivot table of the number of records per state (rows)
+// and severity (columns), with totals, as a header row, one row per state and
+// a total row. Unknown states and severities get a row or column of their own
+// after the allowed ones.
+func summaryRows(tds []TechnicalDebt) [][]any {
+	states := slices.Clone(AllowedStates)
+	severities := append(slices.Clone(AllowedSeverities), "None")
+	counts := make(map[[2]string]int)
+	for _, td := range tds {
+		severity := td.Severity
+		if severity == "" {
+			severity = "None"
+		}
+		if !slices.Contains(states, td.State) {
+			states = append(states, td.State)
+		}
+		if !slices.Contains(severities, severity) {
+			severities = append(severities, severity)
+		}
+		counts[[2]string{td.State, severity}]++
+	}
+
+	rows := [][]any{{"State"}}
+	for _, severity := range severities {
+		rows[0] = append(rows[0], severity)
+	}
+	rows[0] = append(rows[0], "Total")
+	columnTotals := make([]int, len(severities))
+	for _, state := range states {
+		row := []any{state}
+		total := 0
+		for i, severity := range severities {
+			n := counts[[2]string{state, severity}]
+			row = append(row, n)
+			total += n
+			columnTotals[i] += n
+		}
+		rows = append(rows, append(row, total))
+	}
+	totalRow := []any{"Total"}
+	for _, n := range columnTotals {
+		totalRow = append(totalRow, n)
+	}
+	rows = append(rows, append(totalRow, len(tds)))
+	return rows
+}
@@ -0,0 +1,237 @@
+package tdr
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TestWriteWorkbook checks the record table, its frozen header and the summary counts
+func TestWriteWorkbook(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", State: "Identified", Severity: "High", EffortEstimate: 3}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", Title: "Missing Tests", State: "Identified"}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0003", Title: "Slow Build", State: "Closed", Severity: "High"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteWorkbook(&buf, records, nil); err != nil {
+		t.Fatalf("WriteWorkbook() error = %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); !slices.Equal(sheets, []string{excelSummarySheet, excelSheet}) {
+		t.Errorf("sheets = %v, want Summary and TechnicalDebt", sheets)
+	}
+
+	rows, err := f.GetRows(excelSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[0][0] != "ID" || rows[3][1] != "Slow Build" || rows[1][17] != "3" {
+		t.Errorf("record rows = %q", rows)
+	}
+	if panes, _ := f.GetPanes(excelSheet); !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("panes = %+v, want the header row frozen", panes)
+	}
+
+	summary, err := f.GetRows(excelSummarySheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Header, one row per state and the totals
+	if len(summary) != len(AllowedStates)+2 {
+		t.Fatalf("summary has %d rows, want %d", len(summary), len(AllowedStates)+2)
+	}
+	wants := map[string][]string{
+		"State":      {"State", "Critical", "High", "Medium", "Low", "None", "Total"},
+		"Identified": {"Identified", "0", "1", "0", "0", "1", "2"},
+		"Closed":     {"Closed", "0", "1", "0", "0", "0", "1"},
+		"Total":      {"Total", "0", "2", "0", "0", "1", "3"},
+	}
+	for _, row := range summary {
+		if want, ok := wants[row[0]]; ok && !slices.Equal(row, want) {
+			t.Errorf("summary row = %q, want %q", row, want)
+		}
+	}
+}
+
+// TestExcelRendererSheets checks that a single record workbook has no empty default sheet
+func TestExcelRendererSheets(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (ExcelRenderer{}).Render(&buf, TechnicalDebt{Title: "Outdated Library"}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); !slices.Equal(sheets, []string{excelSheet}) {
+		t.Errorf("sheets = %v, want only TechnicalDebt", sheets)
+	}
+}
+
+// TestExcelValidations checks the dropdowns, the date validation and the header comments
+func TestExcelValidations(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (ExcelRenderer{}).Render(&buf, TechnicalDebt{Title: "Outdated Library", Date: "2024-03-01", State: "Identified"}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+
+	validations, err := f.GetDataValidations(excelSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wants := map[string]string{
+		"F2:F1001": `"Identified,Analyzed,Approved,In Progress,Resolved,Closed,Rejected"`,
+		"M2:M1001": `"Critical,High,Medium,Low"`,
+		"E2:E1001": "1",
+	}
+	for _, dv := range validations {
+		if want, ok := wants[dv.Sqref]; !ok || dv.Formula1 != want {
+			t.Errorf("validation %s = %s, want %s", dv.Sqref, dv.Formula1, want)
+		}
+		delete(wants, dv.Sqref)
+	}
+	if len(wants) > 0 {
+		t.Errorf("missing validations %v", wants)
+	}
+
+	if date, _ := f.GetCellValue(excelSheet, "E2"); date != "2024-03-01" {
+		t.Errorf("date = %q, want 2024-03-01", date)
+	}
+
+	comments, err := f.GetComments(excelSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != len(excelHeaders) {
+		t.Errorf("got %d comments, want one per column", len(comments))
+	}
+	for _, c := range comments {
+		if c.Cell == "H1" && !strings.Contains(c.Text, htmlPlaceholders["Summary"]) {
+			t.Errorf("Summary comment = %q", c.Text)
+		}
+	}
+}
+
+// TestReadWorkbook checks that an exported workbook edited in Excel reads back
+func TestReadWorkbook(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", Author: "Jane Doe", Version: "1.0",
+			Date: "2024-03-01", State: "Identified", Relations: []Relation{{Kind: Blocks, Target: "TDR-0002"}}, EffortEstimate: 3}},
+	}
+	var buf bytes.Buffer
+	if err := WriteWorkbook(&buf, records, nil); err != nil {
+		t.Fatalf("WriteWorkbook() error = %v", err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	// A new row, as typed into Excel: the date is a date cell
+	f.SetCellValue(excelSheet, "B3", "Slow Build")
+	f.SetCellValue(excelSheet, "E3", time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC))
+	f.SetCellValue(excelSheet, "F3", "in progress")
+	f.SetCellValue(excelSheet, "G3", "TDR-0001, depends-on TDR-0002")
+	f.SetCellValue(excelSheet, "M3", "HIGH")
+	f.SetCellValue(excelSheet, "S3", 2.5)
+	buf.Reset()
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	rows, err := ReadWorkbook(&buf, nil)
+	if err != nil {
+		t.Fatalf("ReadWorkbook() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != "TDR-0001" || rows[1].ID != "" || rows[1].Row != 3 {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	var td TechnicalDebt
+	if err := rows[0].Apply(&td); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	want := records[0].TechnicalDebt
+	want.ID = ""
+	if !reflect.DeepEqual(td, want) {
+		t.Errorf("exported record = %+v, want %+v", td, want)
+	}
+
+	td = TechnicalDebt{}
+	if err := rows[1].Apply(&td); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	wantRelations := []Relation{{Target: "TDR-0001"}, {Kind: DependsOn, Target: "TDR-0002"}}
+	if td.Title != "Slow Build" || td.Date != "2024-04-15" || td.State != "In Progress" || td.Severity != "High" ||
+		td.CostOfDelayWeekly != 2.5 || !reflect.DeepEqual(td.Relations, wantRelations) {
+		t.Errorf("new record = %+v", td)
+	}
+}
+
+// TestExcelCustomFields checks the columns, validations and read-back of custom fields
+func TestExcelCustomFields(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", State: "Identified",
+			CustomFields: Fields{{"Component", "Backend"}, {"Due Date", "2024-06-30"}, {"Budget", "1.5"}}}},
+	}
+	var buf bytes.Buffer
+	if err := WriteWorkbook(&buf, records, testSchema); err != nil {
+		t.Fatalf("WriteWorkbook() error = %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+
+	// The custom fields follow the built-in columns in the order of the schema
+	rows, err := f.GetRows(excelSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rows[0][len(excelHeaders):]; !slices.Equal(got, []string{"Component", "Jira Key", "Due Date", "Budget"}) {
+		t.Errorf("custom headers = %q", got)
+	}
+	validations, err := f.GetDataValidations(excelSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wants := map[string]string{"X2:X1001": `"Backend,Frontend"`, "Z2:Z1001": "1"}
+	for _, dv := range validations {
+		if want, ok := wants[dv.Sqref]; ok && dv.Formula1 == want {
+			delete(wants, dv.Sqref)
+		}
+	}
+	if len(wants) > 0 {
+		t.Errorf("missing validations %v", wants)
+	}
+
+	imported, err := ReadWorkbook(&buf, testSchema)
+	if err != nil {
+		t.Fatalf("ReadWorkbook() error = %v", err)
+	}
+	var td TechnicalDebt
+	if err := imported[0].Apply(&td); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := records[0].CustomFields; !reflect.DeepEqual(td.CustomFields, want) {
+		t.Errorf("CustomFields = %+v, want %+v", td.CustomFields, want)
+	}
+}
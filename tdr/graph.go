@@ -0,0 +1,293 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GraphFormats lists the formats WriteGraph supports
+var GraphFormats = []string{"dot", "mermaid"}
+
+// severityColors holds the node fill colors, matching the HTML severity badges
+var severityColors = map[string]string{
+	"Critical": "#a40e26",
+	"High":     "#d1242f",
+	"Medium":   "#bf8700",
+	"Low":      "#1a7f37",
+}
+
+// unratedColor fills the nodes of records without a known severity
+const unratedColor = "#6e7781"
+
+// graphShapes holds the node shape per state, as Graphviz shape and Mermaid
+// opening and closing brackets
+var graphShapes = map[string]struct {
+	dot          string
+	mermaidOpen  string
+	mermaidClose string
+}{
+	"Identified":  {"ellipse", "([", "])"},
+	"Analyzed":    {"box", "[", "]"},
+	"Approved":    {"hexagon", "{{", "}}"},
+	"In Progress": {"parallelogram", "[/", "/]"},
+	"Resolved":    {"circle", "((", "))"},
+	"Closed":      {"doublecircle", "(((", ")))"},
+	"Rejected":    {"cds", ">", "]"},
+}
+
+// graphNode is a record or ADR in the dependency graph
+type graphNode struct {
+	ID       string
+	Title    string
+	State    string
+	Severity string
+	// ADR is set for nodes that are ADRs referenced by caused-by relations
+	ADR bool
+}
+
+// graphEdge is a relation drawn from From to To. Inverse kinds are turned
+// around, so that "blocked-by" and "blocks" give the same edge.
+type graphEdge struct {
+	From, To string
+	Kind     RelationKind
+}
+
+// graph is the dependency graph of a set of records
+type graph struct {
+	Nodes []graphNode
+	Edges []graphEdge
+}
+
+// primaryKinds are the kinds edges are drawn with; their inverses are turned around
+var primaryKinds = []RelationKind{RelatesTo, Blocks, DependsOn, Supersedes, Duplicates, CausedBy}
+
+// buildGraph collects the records and their relations. Relations to records
+// that are not part of records are left out; ADRs become nodes of their own.
+func buildGraph(records []Record) graph {
+	var g graph
+	known := make(map[string]bool)
+	for _, rec := range records {
+		g.Nodes = append(g.Nodes, graphNode{ID: rec.ID, Title: rec.Title, State: rec.State, Severity: rec.Severity})
+		known[rec.ID] = true
+	}
+
+	seen := make(map[graphEdge]bool)
+	adrs := make(map[string]bool)
+	for _, rec := range records {
+		for _, rel := range rec.Relations {
+			edge := graphEdge{From: rec.ID, To: rel.Target, Kind: rel.Kind}
+			if !slices.Contains(primaryKinds, rel.Kind) {
+				inverse, _ := rel.Kind.Inverse()
+				edge = graphEdge{From: rel.Target, To: rec.ID, Kind: inverse}
+			}
+			if edge.Kind == RelatesTo && edge.To < edge.From {
+				// Untyped relations have no direction
+				edge.From, edge.To = edge.To, edge.From
+			}
+			switch {
+			case rel.Kind == CausedBy:
+				if !adrs[rel.Target] {
+					adrs[rel.Target] = true
+					g.Nodes = append(g.Nodes, graphNode{ID: rel.Target, ADR: true})
+				}
+			case !known[edge.From] || !known[edge.To]:
+				continue
+			}
+			if !seen[edge] {
+				seen[edge] = true
+				g.Edges = append(g.Edges, edge)
+			}
+		}
+	}
+	return g
+}
+
+// WriteGraph writes the relations between records as a graph in one of
+// GraphFormats. Nodes are colored by severity and shaped by state.
+func WriteGraph(w io.Writer, format string, records []Record) error {
+	var text string
+	switch strings.ToLower(format) {
+	case "dot":
+		text = dotGraph(buildGraph(records))
+	case "mermaid":
+		text = mermaidGraph(buildGraph(records))
+	default:
+		return fmt.Errorf("unsupported graph format %q, supported formats are: %s", format, strings.Join(GraphFormats, ", "))
+	}
+	if _, err := io.WriteString(w, text); err != nil {
+		return fmt.Errorf("error writing graph: %w", err)
+	}
+	return nil
+}
+
+// dotGraph renders the graph in the Graphviz DOT language
+func dotGraph(g graph) string {
+	var b strings.Builder
+	b.WriteString("digraph tdr {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [style=filled, fontcolor=white, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, node := range g.Nodes {
+		if node.ADR {
+			fmt.Fprintf(&b, "  %s [label=%s, shape=note, style=\"\", fontcolor=black];\n", dotQuote(node.ID), dotQuote(node.ID))
+			continue
+		}
+		shape := "box"
+		if s, ok := graphShapes[node.State]; ok {
+			shape = s.dot
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s, fillcolor=%s];\n",
+			dotQuote(node.ID), dotQuote(node.ID+"\n"+node.Title), shape, dotQuote(nodeColor(node)))
+	}
+	for _, edge := range g.Edges {
+		attrs := fmt.Sprintf("label=%s", dotQuote(edgeLabel(edge.Kind)))
+		switch edge.Kind {
+		case RelatesTo:
+			attrs = "dir=none, style=dashed"
+		case CausedBy:
+			attrs += ", style=dotted"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT string, turning newlines into line breaks
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidGraph renders the graph as a Mermaid flowchart
+func mermaidGraph(g graph) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		if node.ADR {
+			fmt.Fprintf(&b, "  %s[[%s]]\n", mermaidID(node.ID), mermaidQuote(node.ID))
+			continue
+		}
+		left, right := "[", "]"
+		if s, ok := graphShapes[node.State]; ok {
+			left, right = s.mermaidOpen, s.mermaidClose
+		}
+		fmt.Fprintf(&b, "  %s%s%s%s\n", mermaidID(node.ID), left, mermaidQuote(node.ID+"<br/>"+node.Title), right)
+	}
+	for _, edge := range g.Edges {
+		switch edge.Kind {
+		case RelatesTo:
+			fmt.Fprintf(&b, "  %s -.- %s\n", mermaidID(edge.From), mermaidID(edge.To))
+		case CausedBy:
+			fmt.Fprintf(&b, "  %s -.->|%s| %s\n", mermaidID(edge.From), edgeLabel(edge.Kind), mermaidID(edge.To))
+		default:
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", mermaidID(edge.From), edgeLabel(edge.Kind), mermaidID(edge.To))
+		}
+	}
+
+	// One class per severity, so the colors are defined once
+	classes := make(map[string][]string)
+	colors := make(map[string]string)
+	for _, node := range g.Nodes {
+		if !node.ADR {
+			class := "unrated"
+			if _, ok := severityColors[node.Severity]; ok {
+				class = strings.ToLower(node.Severity)
+			}
+			classes[class] = append(classes[class], mermaidID(node.ID))
+			colors[class] = nodeColor(node)
+		}
+	}
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  classDef %s fill:%s,color:#fff\n", name, colors[name])
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(classes[name], ","), name)
+	}
+	return b.String()
+}
+
+// mermaidID turns a record ID into a Mermaid node ID, e.g. "TDR-0007" -> "TDR_0007"
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidQuote quotes s as a Mermaid node label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// nodeColor returns the fill color for a record node
+func nodeColor(node graphNode) string {
+	if color, ok := severityColors[node.Severity]; ok {
+		return color
+	}
+	return unratedColor
+}
+
+// edgeLabel returns the edge text for a kind, e.g. "depends on"
+func edgeLabel(kind RelationKind) string {
+	if kind == CausedBy {
+		return "caused by"
+	}
+	return strings.ToLower(kind.Label())
+}
+
+// dependencyCycles returns the cycles formed by blocks and depends-on relations
+// (and their inverses) between records. Each cycle lists the record IDs in
+// order and ends with the ID it started with.
+func dependencyCycles(records []Record) [][]string {
+	// Edges point from a record to the records that must be resolved before it
+	before := make(map[string][]string)
+	for _, edge := range buildGraph(records).Edges {
+		switch edge.Kind {
+		case DependsOn:
+			before[edge.From] = append(before[edge.From], edge.To)
+		case Blocks:
+			before[edge.To] = append(before[edge.To], edge.From)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, next := range before[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				start := slices.Index(stack, next)
+				cycle := append(slices.Clone(stack[start:]), next)
+				cycles = append(cycles, cycle)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, rec := range records {
+		if state[rec.ID] == unvisited {
+			visit(rec.ID)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,79 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// graphRecords returns three records: TDR-0002 blocks TDR-0001, TDR-0003 depends on TDR-0001
+func graphRecords() []Record {
+	return []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: `Old "auth"`, State: "Identified", Severity: "Critical",
+			Relations: []Relation{{Kind: BlockedBy, Target: "TDR-0002"}, {Kind: RequiredBy, Target: "TDR-0003"}}}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", Title: "Missing tests", State: "In Progress",
+			Relations: []Relation{{Kind: Blocks, Target: "TDR-0001"}, {Kind: CausedBy, Target: "ADR-0012"}, {Target: "TDR-0003"}}}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0003", Title: "Slow build", State: "Closed", Severity: "Low",
+			Relations: []Relation{{Kind: DependsOn, Target: "TDR-0001"}, {Target: "TDR-0002"}, {Target: "TDR-0042"}}}},
+	}
+}
+
+// TestWriteGraph checks nodes, shapes, colors and deduplicated edges in both formats
+func TestWriteGraph(t *testing.T) {
+	var dot strings.Builder
+	if err := WriteGraph(&dot, "dot", graphRecords()); err != nil {
+		t.Fatalf("WriteGraph(dot) error = %v", err)
+	}
+	for _, want := range []string{
+		`"TDR-0001" [label="TDR-0001\nOld \"auth\"", shape=ellipse, fillcolor="#a40e26"];`,
+		`"TDR-0002" [label="TDR-0002\nMissing tests", shape=parallelogram, fillcolor="#6e7781"];`,
+		`"ADR-0012" [label="ADR-0012", shape=note`,
+		`"TDR-0002" -> "TDR-0001" [label="blocks"];`,
+		`"TDR-0003" -> "TDR-0001" [label="depends on"];`,
+		`"TDR-0002" -> "ADR-0012" [label="caused by", style=dotted];`,
+		`"TDR-0002" -> "TDR-0003" [dir=none, style=dashed];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT graph does not contain %q:\n%s", want, dot.String())
+		}
+	}
+	if n := strings.Count(dot.String(), " -> "); n != 4 {
+		t.Errorf("DOT graph has %d edges, want 4:\n%s", n, dot.String())
+	}
+
+	var mermaid strings.Builder
+	if err := WriteGraph(&mermaid, "Mermaid", graphRecords()); err != nil {
+		t.Fatalf("WriteGraph(mermaid) error = %v", err)
+	}
+	for _, want := range []string{
+		"flowchart LR\n",
+		`TDR_0001(["TDR-0001<br/>Old #quot;auth#quot;"])`,
+		`TDR_0003((("TDR-0003<br/>Slow build")))`,
+		"TDR_0002 -->|blocks| TDR_0001",
+		"TDR_0002 -.- TDR_0003",
+		"classDef critical fill:#a40e26,color:#fff\n  class TDR_0001 critical",
+	} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("Mermaid graph does not contain %q:\n%s", want, mermaid.String())
+		}
+	}
+
+	if err := WriteGraph(&dot, "svg", nil); err == nil {
+		t.Error("WriteGraph(svg) succeeded, want error")
+	}
+}
+
+// TestDependencyCycles checks that blocks and depends-on chains are followed in both directions
+func TestDependencyCycles(t *testing.T) {
+	records := graphRecords()
+	if cycles := dependencyCycles(records); len(cycles) != 0 {
+		t.Errorf("dependencyCycles() = %v, want none", cycles)
+	}
+
+	// TDR-0001 depends on TDR-0003, which depends on TDR-0001
+	records[0].Relations = append(records[0].Relations, Relation{Kind: DependsOn, Target: "TDR-0003"})
+	want := [][]string{{"TDR-0001", "TDR-0003", "TDR-0001"}}
+	if cycles := dependencyCycles(records); !reflect.DeepEqual(cycles, want) {
+		t.Errorf("dependencyCycles() = %v, want %v", cycles, want)
+	}
+}
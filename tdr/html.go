@@ -0,0 +1,274 @@
+package tdr
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// HTMLRenderer renders a record as a self-contained HTML5 page with embedded CSS
+type HTMLRenderer struct {
+	// Links resolves relations to record files; without it relations link to "<ID>.html"
+	Links LinkResolver
+}
+
+// Name implements Renderer
+func (HTMLRenderer) Name() string { return "html" }
+
+// Extension implements Renderer
+func (HTMLRenderer) Extension() string { return ".html" }
+
+// Render implements Renderer
+func (r HTMLRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	if err := htmlTemplate.Execute(w, newHTMLPage(td, r.Links)); err != nil {
+		return fmt.Errorf("error writing HTML: %w", err)
+	}
+	return nil
+}
+
+// htmlPage is the data passed to htmlTemplate
+type htmlPage struct {
+	ID        string
+	Title     string
+	Author    string
+	Version   string
+	Date      string
+	State     string
+	Severity  string
+	Relations []htmlRelations
+	Sections  []htmlSection
+	History   []Transition
+	Empty     bool
+}
+
+// htmlRelations is a group of relations of one kind; Label is empty for untyped relations
+type htmlRelations struct {
+	Label string
+	Links []htmlLink
+}
+
+// htmlLink is a relation rendered as a link to the related record; ADR
+// references have no Href
+type htmlLink struct {
+	Label string
+	Href  string
+}
+
+// htmlSection is a titled block of text; Level 3 sections are nested under the previous one
+type htmlSection struct {
+	Title   string
+	Content string
+	Level   int
+}
+
+// newHTMLPage prepares the record for the HTML template, using placeholders for empty templates
+func newHTMLPage(td TechnicalDebt, links LinkResolver) htmlPage {
+	page := htmlPage{
+		ID:       td.ID,
+		Title:    td.Title,
+		Author:   td.Author,
+		Version:  td.Version,
+		Date:     td.Date,
+		State:    td.State,
+		Severity: td.Severity,
+		History:  td.History,
+		Empty:    td.Empty,
+	}
+	for _, group := range groupRelations(td.Relations) {
+		relations := htmlRelations{}
+		if group.Kind != RelatesTo {
+			relations.Label = group.Kind.Label()
+		}
+		for _, rel := range group.Relations {
+			if !group.Kind.RefersToRecord() {
+				relations.Links = append(relations.Links, htmlLink{Label: rel.Target})
+				continue
+			}
+			name, ok := links.resolve(rel.Target)
+			if !ok {
+				name = rel.Target
+			}
+			relations.Links = append(relations.Links, htmlLink{Label: rel.Target, Href: url.PathEscape(name) + ".html"})
+		}
+		page.Relations = append(page.Relations, relations)
+	}
+
+	sections := []htmlSection{
+		{"Summary", td.Summary, 2},
+		{"Context", td.Context, 2},
+		{"Technical Impact", td.ImpactTech, 3},
+		{"Business Impact", td.ImpactBus, 3},
+		{"Symptoms", td.Symptoms, 2},
+		{"Potential Risks", td.PotentialRisks, 2},
+		{"Proposed Solution", td.ProposedSol, 2},
+		{"Cost of Delay", td.CostDelay, 2},
+		{"Effort to Resolve", td.Effort, 2},
+	}
+	if !td.Empty {
+		for _, e := range estimates(&td) {
+			if *e.Value != 0 {
+				sections = append(sections, htmlSection{e.Heading, FormatNumber(*e.Value), 2})
+			}
+		}
+	}
+	sections = append(sections,
+		htmlSection{"Dependencies", td.Dependencies, 2},
+		htmlSection{"Additional Notes", td.Additional, 2},
+	)
+
+	if td.Empty {
+		page.Title = "[Enter Title Here]"
+		page.Author = "[Enter Author Here]"
+		page.Version = "[Enter Version Here]"
+		page.Date = "[Enter Date Here]"
+		page.State = "[Enter State Here]"
+		page.Severity = "[Enter Severity Here: Critical / High / Medium / Low]"
+		for i := range sections {
+			sections[i].Content = htmlPlaceholders[sections[i].Title]
+		}
+	}
+	// Custom fields follow the additional notes; empty templates hold their placeholders
+	for _, field := range td.CustomFields {
+		sections = append(sections, htmlSection{field.Name, field.Value, 2})
+	}
+	page.Sections = sections
+	return page
+}
+
+// htmlPlaceholders holds the hints shown in empty templates, matching GenerateMarkdown
+var htmlPlaceholders = map[string]string{
+	"Summary":           "A brief overview of the technical debt, explaining the problem in one or two sentences.",
+	"Context":           "Provide the historical context and reasons why this technical debt exists.",
+	"Technical Impact":  "Describe how the debt affects the system’s performance, scalability, or maintainability.",
+	"Business Impact":   "Explain how the debt affects the business, such as increased risk, customer dissatisfaction, or slower feature delivery.",
+	"Symptoms":          "List specific signs that indicate the presence of technical debt.",
+	"Potential Risks":   "Potential security vulnerabilities leading to data breaches.",
+	"Proposed Solution": "Describe how to resolve the technical debt.",
+	"Cost of Delay":     "Explain the consequences of delaying the resolution of the technical debt.",
+	"Effort to Resolve": "Estimate the time, resources, and effort needed to address the debt.",
+	"Dependencies":      "List any dependencies or blockers that need to be resolved before tackling the debt.",
+	"Additional Notes":  "Any other relevant information or considerations.",
+}
+
+// badgeClass turns a state or severity into a CSS class name, e.g. "In Progress" -> "in-progress"
+func badgeClass(value string) string {
+	class := strings.ToLower(strings.Join(strings.Fields(value), "-"))
+	for _, severity := range AllowedSeverities {
+		if strings.EqualFold(value, severity) {
+			return class
+		}
+	}
+	for _, state := range AllowedStates {
+		if strings.EqualFold(value, state) {
+			return class
+		}
+	}
+	return "unknown"
+}
+
+// htmlStyle is the embedded style sheet shared by the record and report pages
+const htmlStyle = `  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #1f2328; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; }
+  header { border-bottom: 2px solid #d0d7de; margin-bottom: 1.5rem; padding-bottom: 1rem; }
+  header p.kind { text-transform: uppercase; letter-spacing: .08em; font-size: .8rem; color: #59636e; margin: 0; }
+  h1 { margin: .25rem 0 .75rem; font-size: 1.9rem; }
+  h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .25rem; margin-top: 2rem; font-size: 1.3rem; }
+  h3 { font-size: 1.05rem; margin-bottom: .25rem; }
+  dl.meta { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; margin: 0; }
+  dl.meta dt { font-weight: 600; color: #59636e; }
+  dl.meta dd { margin: 0; }
+  .badge { display: inline-block; padding: .1rem .6rem; border-radius: 1rem; font-size: .85rem; font-weight: 600; color: #fff; background: #6e7781; }
+  .badge.critical { background: #a40e26; }
+  .badge.high { background: #d1242f; }
+  .badge.medium { background: #bf8700; }
+  .badge.low { background: #1a7f37; }
+  .badge.identified { background: #8250df; }
+  .badge.analyzed { background: #0969da; }
+  .badge.approved { background: #1f6feb; }
+  .badge.in-progress { background: #bc4c00; }
+  .badge.resolved { background: #1a7f37; }
+  .badge.closed { background: #57606a; }
+  .badge.rejected { background: #82071e; }
+  .content { white-space: pre-wrap; }
+  .placeholder { font-style: italic; color: #59636e; }
+  ul.relations { padding-left: 1.25rem; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { border: 1px solid #d0d7de; padding: .3rem .6rem; text-align: left; }
+  th { background: #f6f8fa; }
+`
+
+var htmlTemplate = template.Must(template.New("tdr").Funcs(template.FuncMap{
+	"badgeClass": badgeClass,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .ID}}{{.ID}}: {{else}}Technical Debt Record: {{end}}{{.Title}}</title>
+<style>
+` + htmlStyle + `</style>
+</head>
+<body>
+<header>
+  <p class="kind">Technical Debt Record{{if .ID}} &middot; {{.ID}}{{end}}</p>
+  <h1>{{.Title}}</h1>
+  <dl class="meta">
+    <dt>Author</dt><dd>{{.Author}}</dd>
+    <dt>Version</dt><dd>{{.Version}}</dd>
+    <dt>Date</dt><dd>{{.Date}}</dd>
+    <dt>State</dt><dd>{{if .Empty}}<span class="placeholder">{{.State}}</span>{{else}}<span class="badge state {{badgeClass .State}}">{{.State}}</span>{{end}}</dd>
+    <dt>Severity</dt><dd>{{if .Empty}}<span class="placeholder">{{.Severity}}</span>{{else if .Severity}}<span class="badge severity {{badgeClass .Severity}}">{{.Severity}}</span>{{else}}&ndash;{{end}}</dd>
+  </dl>
+</header>
+<main>
+<section>
+<h2>Relations</h2>
+{{- range .Relations}}
+{{- if .Label}}
+<h3>{{.Label}}</h3>
+{{- end}}
+<ul class="relations">
+{{- range .Links}}
+  <li>{{if .Href}}<a href="{{.Href}}">{{.Label}}</a>{{else}}{{.Label}}{{end}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>None</p>
+{{- end}}
+</section>
+{{- range $i, $s := .Sections}}
+{{- if eq $s.Title "Technical Impact"}}
+<section>
+<h2>Impact</h2>
+{{- end}}
+{{- if eq $s.Level 3}}
+<h3>{{$s.Title}}</h3>
+<div class="content{{if $.Empty}} placeholder{{end}}">{{$s.Content}}</div>
+{{- if eq $s.Title "Business Impact"}}
+</section>
+{{- end}}
+{{- else}}
+<section>
+<h2>{{$s.Title}}</h2>
+<div class="content{{if $.Empty}} placeholder{{end}}">{{$s.Content}}</div>
+</section>
+{{- end}}
+{{- end}}
+{{- if .History}}
+<section>
+<h2>History</h2>
+<table class="history">
+<thead><tr><th>Date</th><th>From</th><th>To</th><th>Actor</th></tr></thead>
+<tbody>
+{{- range .History}}
+<tr><td>{{.Date}}</td><td>{{.From}}</td><td>{{.To}}</td><td>{{.Actor}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
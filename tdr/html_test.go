@@ -0,0 +1,61 @@
+package tdr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestHTMLRenderer checks the badges, relation links and escaping of the HTML page
+func TestHTMLRenderer(t *testing.T) {
+	td := TechnicalDebt{
+		Title:     "Outdated <script>alert(1)</script> Library",
+		Author:    "Jane & John",
+		Version:   "1.0.0",
+		Date:      "2024-04-15",
+		State:     "In Progress",
+		Relations: []Relation{{Target: "TDR-102"}, {Target: `"><img src=x>`}},
+		Summary:   "Uses <b>raw</b> HTML.",
+		Severity:  "Critical",
+	}
+
+	var buf bytes.Buffer
+	if err := (HTMLRenderer{}).Render(&buf, td); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	page := buf.String()
+
+	wants := []string{
+		"<!DOCTYPE html>",
+		"<style>",
+		`<span class="badge state in-progress">In Progress</span>`,
+		`<span class="badge severity critical">Critical</span>`,
+		`<a href="TDR-102.html">TDR-102</a>`,
+		"Outdated &lt;script&gt;alert(1)&lt;/script&gt; Library",
+		"Jane &amp; John",
+		"Uses &lt;b&gt;raw&lt;/b&gt; HTML.",
+	}
+	for _, want := range wants {
+		if !strings.Contains(page, want) {
+			t.Errorf("Render() output lacks %q", want)
+		}
+	}
+	for _, unwanted := range []string{"<script>", "<b>raw", "<img"} {
+		if strings.Contains(page, unwanted) {
+			t.Errorf("Render() output contains unescaped %q", unwanted)
+		}
+	}
+}
+
+// TestHTMLRendererEmpty checks that empty templates show the placeholders
+func TestHTMLRendererEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (HTMLRenderer{}).Render(&buf, TechnicalDebt{Empty: true}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"[Enter Title Here]", htmlPlaceholders["Summary"]} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Render() output lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package tdr
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImportRow is a record read from a row of a table, such as a workbook written
+// by WriteWorkbook, with the columns named by the headers of the Excel export.
+// Other columns hold custom fields.
+type ImportRow struct {
+	// Row is the number of the row in the table, counting the header as 1
+	Row int
+	// ID is the value of the ID column, empty for records not yet in the repository
+	ID string
+	// values holds the cells by header, for the columns present in the table
+	values map[string]string
+	// custom are the headers of the custom field columns, in table order
+	custom []string
+}
+
+// importFields sets the field of a column from its cell value
+var importFields = map[string]func(td *TechnicalDebt, value string) error{
+	"Title":             func(td *TechnicalDebt, v string) error { td.Title = v; return nil },
+	"Author":            func(td *TechnicalDebt, v string) error { td.Author = v; return nil },
+	"Version":           func(td *TechnicalDebt, v string) error { td.Version = v; return nil },
+	"Date":              func(td *TechnicalDebt, v string) error { td.Date = v; return nil },
+	"State":             importState,
+	"Relations":         importRelations,
+	"Summary":           func(td *TechnicalDebt, v string) error { td.Summary = v; return nil },
+	"Context":           func(td *TechnicalDebt, v string) error { td.Context = v; return nil },
+	"Technical Impact":  func(td *TechnicalDebt, v string) error { td.ImpactTech = v; return nil },
+	"Business Impact":   func(td *TechnicalDebt, v string) error { td.ImpactBus = v; return nil },
+	"Symptoms":          func(td *TechnicalDebt, v string) error { td.Symptoms = v; return nil },
+	"Severity":          importSeverity,
+	"Potential Risks":   func(td *TechnicalDebt, v string) error { td.PotentialRisks = v; return nil },
+	"Proposed Solution": func(td *TechnicalDebt, v string) error { td.ProposedSol = v; return nil },
+	"Cost of Delay":     func(td *TechnicalDebt, v string) error { td.CostDelay = v; return nil },
+	"Effort to Resolve": func(td *TechnicalDebt, v string) error { td.Effort = v; return nil },
+	"Effort Estimate":   func(td *TechnicalDebt, v string) error { return importNumber(&td.EffortEstimate, v) },
+	"Cost of Delay per Week": func(td *TechnicalDebt, v string) error {
+		return importNumber(&td.CostOfDelayWeekly, v)
+	},
+	"Risk Reduction": func(td *TechnicalDebt, v string) error { return importNumber(&td.RiskReduction, v) },
+	"Interest Rate (Hours per Week)": func(td *TechnicalDebt, v string) error {
+		return importNumber(&td.InterestRate, v)
+	},
+	"Dependencies":     func(td *TechnicalDebt, v string) error { td.Dependencies = v; return nil },
+	"Additional Notes": func(td *TechnicalDebt, v string) error { td.Additional = v; return nil },
+}
+
+// importState accepts a state in any case, leaving unknown states for Validate
+func importState(td *TechnicalDebt, value string) error {
+	td.State = value
+	if state, err := ParseState(value); err == nil {
+		td.State = state
+	}
+	return nil
+}
+
+// importSeverity accepts a severity in any case like normalizeSeverity
+func importSeverity(td *TechnicalDebt, value string) error {
+	td.Severity = value
+	normalizeSeverity(td)
+	return nil
+}
+
+// importRelations reads a comma-separated list of relations, see ParseRelation
+func importRelations(td *TechnicalDebt, value string) error {
+	td.Relations = nil
+	for _, field := range strings.Split(value, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		relation, err := ParseRelation(field)
+		if err != nil {
+			return fmt.Errorf("invalid relation %q: %w", strings.TrimSpace(field), err)
+		}
+		td.Relations = append(td.Relations, relation)
+	}
+	return nil
+}
+
+// importNumber reads an estimate, treating an empty cell as zero
+func importNumber(v *float64, value string) error {
+	if value == "" {
+		*v = 0
+		return nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", value)
+	}
+	*v = n
+	return nil
+}
+
+// Apply sets the fields of td from the columns present in the row; fields
+// without a column are left unchanged, so that a table with some of the
+// columns updates only those fields of an existing record. Custom fields are
+// matched ignoring case, and empty cells do not add custom fields td lacks.
+func (row ImportRow) Apply(td *TechnicalDebt) error {
+	// Apply the columns in a fixed order, so that errors are reproducible
+	for _, header := range excelHeaders {
+		value, ok := row.values[header]
+		set := importFields[header]
+		if !ok || set == nil {
+			continue
+		}
+		if err := set(td, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("row %d, column %s: %w", row.Row, header, err)
+		}
+	}
+	for _, name := range row.custom {
+		value := strings.TrimSpace(row.values[name])
+		i := slices.IndexFunc(td.CustomFields, func(field Field) bool { return strings.EqualFold(field.Name, name) })
+		switch {
+		case i >= 0:
+			td.CustomFields[i].Value = value
+		case value != "":
+			td.CustomFields.Set(name, value)
+		}
+	}
+	return nil
+}
+
+// importRows turns a table into rows. The first row holds the headers, which
+// are the columns of the Excel export or custom fields; blank rows are skipped.
+func importRows(table [][]string) ([]ImportRow, error) {
+	if len(table) == 0 {
+		return nil, fmt.Errorf("the table is empty, expected a header row")
+	}
+	headers := make([]string, len(table[0]))
+	seen := make(map[string]bool)
+	var custom []string
+	for i, cell := range table[0] {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		index := -1
+		for j, header := range excelHeaders {
+			if strings.EqualFold(cell, header) {
+				index = j
+			}
+		}
+		if index < 0 {
+			if slices.ContainsFunc(custom, func(name string) bool { return strings.EqualFold(name, cell) }) {
+				return nil, fmt.Errorf("duplicate column %q", cell)
+			}
+			custom = append(custom, cell)
+			headers[i] = cell
+			continue
+		}
+		if seen[excelHeaders[index]] {
+			return nil, fmt.Errorf("duplicate column %q", cell)
+		}
+		seen[excelHeaders[index]] = true
+		headers[i] = excelHeaders[index]
+	}
+	if !seen["Title"] {
+		return nil, fmt.Errorf("the header row has no Title column")
+	}
+
+	var rows []ImportRow
+	for i, cells := range table[1:] {
+		row := ImportRow{Row: i + 2, values: make(map[string]string), custom: custom}
+		blank := true
+		for j, header := range headers {
+			if header == "" {
+				continue
+			}
+			value := ""
+			if j < len(cells) {
+				value = cells[j]
+			}
+			if strings.TrimSpace(value) != "" {
+				blank = false
+			}
+			if header == "ID" {
+				row.ID = strings.TrimSpace(value)
+				continue
+			}
+			row.values[header] = value
+		}
+		if !blank {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,85 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestImportRows checks the header mapping and that missing columns leave fields unchanged
+func TestImportRows(t *testing.T) {
+	rows, err := importRows([][]string{
+		{"id", "Title", "Severity", "Effort Estimate"},
+		{"TDR-0001", "Outdated Library", "low", "5"},
+		{"", "", "", ""},
+		{"", "Slow Build"},
+	})
+	if err != nil {
+		t.Fatalf("importRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != "TDR-0001" || rows[1].Row != 4 {
+		t.Fatalf("rows = %+v, want the blank row skipped", rows)
+	}
+
+	td := TechnicalDebt{Title: "Old Title", Author: "Jane Doe", Severity: "High"}
+	if err := rows[0].Apply(&td); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if td.Title != "Outdated Library" || td.Author != "Jane Doe" || td.Severity != "Low" || td.EffortEstimate != 5 {
+		t.Errorf("Apply() = %+v", td)
+	}
+	// Short rows have empty cells
+	if err := rows[1].Apply(&td); err != nil || td.Severity != "" || td.EffortEstimate != 0 {
+		t.Errorf("Apply() = %+v, %v, want severity and estimate cleared", td, err)
+	}
+}
+
+// TestImportRowsCustomFields checks that other columns are read as custom fields
+func TestImportRowsCustomFields(t *testing.T) {
+	rows, err := importRows([][]string{
+		{"Title", "component", "Jira Key"},
+		{"Outdated Library", "Frontend", ""},
+	})
+	if err != nil {
+		t.Fatalf("importRows() error = %v", err)
+	}
+	td := TechnicalDebt{CustomFields: Fields{{"Component", "Backend"}}}
+	if err := rows[0].Apply(&td); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	// The existing field keeps its name and the empty Jira Key is not added
+	if want := (Fields{{"Component", "Frontend"}}); !reflect.DeepEqual(td.CustomFields, want) {
+		t.Errorf("CustomFields = %+v, want %+v", td.CustomFields, want)
+	}
+}
+
+// TestImportRowsInvalid checks that malformed tables and cells are reported
+func TestImportRowsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		table [][]string
+		want  string
+	}{
+		{name: "Empty", table: nil, want: "header row"},
+		{name: "Duplicate Custom Column", table: [][]string{{"Title", "Owner", "owner"}}, want: `duplicate column "owner"`},
+		{name: "Duplicate Column", table: [][]string{{"Title", "title"}}, want: "duplicate column"},
+		{name: "No Title", table: [][]string{{"ID", "State"}}, want: "no Title column"},
+		{name: "Invalid Number", table: [][]string{{"Title", "Risk Reduction"}, {"Slow Build", "a lot"}}, want: "row 2, column Risk Reduction"},
+		{name: "Invalid Relation", table: [][]string{{"Title", "Relations"}, {"Slow Build", "fixes TDR-0001"}}, want: "invalid relation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := importRows(tt.table)
+			if err == nil {
+				for _, row := range rows {
+					if err = row.Apply(&TechnicalDebt{}); err != nil {
+						break
+					}
+				}
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tdr
+
+import (
+	"sort"
+	"time"
+)
+
+// hoursPerWeek converts durations to the weeks interest rates are given in
+const hoursPerWeek = 7 * 24
+
+// InterestPeriod returns the period over which the record accrues interest:
+// from its Date until now, or until it was settled according to its history.
+// The period is empty if the date is invalid or lies in the future, and for
+// settled records whose history does not tell when they were settled.
+func (td TechnicalDebt) InterestPeriod(now time.Time) (start, end time.Time) {
+	start, err := time.Parse(DateFormat, td.Date)
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	// Only whole days count, like the dates of the record
+	end, _ = time.Parse(DateFormat, now.Format(DateFormat))
+	if Settled(td.State) {
+		end = start
+		for _, t := range td.History {
+			if Settled(t.To) {
+				if date, err := time.Parse(DateFormat, t.Date); err == nil {
+					end = date
+				}
+				break
+			}
+		}
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+// Interest returns the hours lost to the debt so far, at InterestRate hours per
+// week over its InterestPeriod
+func (td TechnicalDebt) Interest(now time.Time) float64 {
+	start, end := td.InterestPeriod(now)
+	return td.InterestRate * end.Sub(start).Hours() / hoursPerWeek
+}
+
+// RecordInterest is the interest accrued by a single record
+type RecordInterest struct {
+	Record
+	// Weeks is the length of the interest period
+	Weeks float64
+	// Hours is the interest accrued over the period
+	Hours float64
+}
+
+// InterestGroup sums the interest of the records sharing a state or severity
+type InterestGroup struct {
+	// Name is the state or severity, or "None" for records without a severity
+	Name    string
+	Records int
+	Hours   float64
+}
+
+// InterestReport is the interest accrued by the records of a repository
+type InterestReport struct {
+	// Records lists the records with an interest rate, highest interest first
+	Records    []RecordInterest
+	ByState    []InterestGroup
+	BySeverity []InterestGroup
+	// Total is the interest accrued by all records
+	Total float64
+}
+
+// AccruedInterest computes the interest of every record with an interest rate
+// as of now, in total and grouped by state and severity. Groups are ordered
+// as AllowedStates and AllowedSeverities and only listed if they have records.
+func AccruedInterest(records []Record, now time.Time) InterestReport {
+	var report InterestReport
+	states := make(map[string]*InterestGroup)
+	severities := make(map[string]*InterestGroup)
+	add := func(groups map[string]*InterestGroup, name string, hours float64) {
+		if groups[name] == nil {
+			groups[name] = &InterestGroup{Name: name}
+		}
+		groups[name].Records++
+		groups[name].Hours += hours
+	}
+
+	for _, rec := range records {
+		if rec.InterestRate == 0 {
+			continue
+		}
+		start, end := rec.InterestPeriod(now)
+		hours := rec.Interest(now)
+		report.Records = append(report.Records, RecordInterest{
+			Record: rec,
+			Weeks:  end.Sub(start).Hours() / hoursPerWeek,
+			Hours:  hours,
+		})
+		report.Total += hours
+		add(states, rec.State, hours)
+		severity := rec.Severity
+		if severity == "" {
+			severity = "None"
+		}
+		add(severities, severity, hours)
+	}
+
+	sort.SliceStable(report.Records, func(i, j int) bool {
+		return report.Records[i].Hours > report.Records[j].Hours
+	})
+	report.ByState = orderedGroups(states, AllowedStates)
+	report.BySeverity = orderedGroups(severities, append(append([]string(nil), AllowedSeverities...), "None"))
+	return report
+}
+
+// orderedGroups returns the groups in the given order of names. Groups with
+// names that are not in order, such as invalid states, come last.
+func orderedGroups(groups map[string]*InterestGroup, order []string) []InterestGroup {
+	var result []InterestGroup
+	for _, name := range order {
+		if group, ok := groups[name]; ok {
+			result = append(result, *group)
+			delete(groups, name)
+		}
+	}
+	var rest []InterestGroup
+	for _, group := range groups {
+		rest = append(rest, *group)
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Name < rest[j].Name })
+	return append(result, rest...)
+}
@@ -0,0 +1,57 @@
+package tdr
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestAccruedInterest checks the interest per record, its end on settlement and the groups
+func TestAccruedInterest(t *testing.T) {
+	now := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Date: "2024-02-23", State: "Identified", Severity: "High", InterestRate: 2}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", Date: "2024-01-01", State: "Identified"}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0003", Date: "2024-02-02", State: "Resolved", Severity: "High", InterestRate: 4,
+			History: []Transition{
+				{Date: "2024-02-09", From: "Identified", To: "Analyzed"},
+				{Date: "2024-02-16", From: "In Progress", To: "Resolved"},
+			}}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0004", Date: "2024-03-15", State: "Analyzed", InterestRate: 1}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0005", Date: "2020-01-01", State: "Closed", InterestRate: 10}},
+	}
+
+	report := AccruedInterest(records, now)
+	var got []string
+	for _, r := range report.Records {
+		got = append(got, r.ID)
+	}
+	want := []string{"TDR-0003", "TDR-0001", "TDR-0004", "TDR-0005"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("records = %v, want %v", got, want)
+	}
+	if r := report.Records[0]; r.Weeks != 2 || r.Hours != 8 {
+		t.Errorf("settled record: weeks = %v, hours = %v, want 2 and 8", r.Weeks, r.Hours)
+	}
+	if r := report.Records[1]; r.Weeks != 1 || r.Hours != 2 {
+		t.Errorf("open record: weeks = %v, hours = %v, want 1 and 2", r.Weeks, r.Hours)
+	}
+	if r := report.Records[2]; r.Hours != 0 {
+		t.Errorf("future record: hours = %v, want 0", r.Hours)
+	}
+	if r := report.Records[3]; r.Hours != 0 {
+		t.Errorf("settled record without history: hours = %v, want 0", r.Hours)
+	}
+	if report.Total != 10 {
+		t.Errorf("Total = %v, want 10", report.Total)
+	}
+
+	wantStates := []InterestGroup{{"Identified", 1, 2}, {"Analyzed", 1, 0}, {"Resolved", 1, 8}, {"Closed", 1, 0}}
+	if !slices.Equal(report.ByState, wantStates) {
+		t.Errorf("ByState = %v, want %v", report.ByState, wantStates)
+	}
+	wantSeverities := []InterestGroup{{"High", 2, 10}, {"None", 2, 0}}
+	if !slices.Equal(report.BySeverity, wantSeverities) {
+		t.Errorf("BySeverity = %v, want %v", report.BySeverity, wantSeverities)
+	}
+}
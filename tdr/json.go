@@ -0,0 +1,82 @@
+package tdr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SchemaVersion is the version of the JSON and YAML record schema described in
+// docs/schema.md. It is increased whenever a key is renamed or removed.
+const SchemaVersion = 1
+
+// Document is the versioned JSON and YAML representation of a record
+type Document struct {
+	SchemaVersion int `json:"schema_version" yaml:"schema_version"`
+	TechnicalDebt `yaml:",inline"`
+}
+
+// NewDocument wraps a record in a document of the current schema version
+func NewDocument(td TechnicalDebt) Document {
+	return Document{SchemaVersion: SchemaVersion, TechnicalDebt: td}
+}
+
+// checkSchemaVersion rejects documents written for a newer or unknown schema
+func checkSchemaVersion(version int) error {
+	if version < 0 || version > SchemaVersion {
+		return fmt.Errorf("unsupported schema_version %d, this version supports up to %d", version, SchemaVersion)
+	}
+	return nil
+}
+
+// JSONRenderer renders a record as a JSON document
+type JSONRenderer struct{}
+
+// Name implements Renderer
+func (JSONRenderer) Name() string { return "json" }
+
+// Extension implements Renderer
+func (JSONRenderer) Extension() string { return ".json" }
+
+// Render implements Renderer
+func (JSONRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	if td.Empty {
+		return writeJSONTemplate(w, td.CustomFields)
+	}
+	data, err := json.MarshalIndent(NewDocument(td), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeJSONTemplate writes a document listing every key, and the given custom
+// fields, with an empty value
+func writeJSONTemplate(w io.Writer, fields Fields) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{\n  \"schema_version\": %d", SchemaVersion)
+	for _, key := range recordKeys("json") {
+		value := `""`
+		switch {
+		case isFieldsKey(key):
+			var names []string
+			for _, field := range fields {
+				names = append(names, fmt.Sprintf("\n    %q: \"\"", field.Name))
+			}
+			value = "{}"
+			if len(names) > 0 {
+				value = "{" + strings.Join(names, ",") + "\n  }"
+			}
+		case isListKey(key):
+			value = "[]"
+		case isNumberKey(key):
+			value = "0"
+		}
+		fmt.Fprintf(&b, ",\n  %q: %s", key, value)
+	}
+	b.WriteString("\n}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
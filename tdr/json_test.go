@@ -0,0 +1,79 @@
+package tdr
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestJSONAndYAMLRoundTrip checks that rendered documents decode to the original record
+func TestJSONAndYAMLRoundTrip(t *testing.T) {
+	td := TechnicalDebt{
+		Title:          "Outdated Library",
+		Author:         "Jane Doe",
+		Version:        "1.0.0",
+		Date:           "2024-04-15",
+		State:          "Analyzed",
+		Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
+		Summary:        "The library is outdated and causes security vulnerabilities.",
+		Context:        "Originally chosen for quick implementation.\nNever revisited.",
+		ImpactTech:     "Security risks and maintainability issues.",
+		ImpactBus:      "Impact on customer satisfaction.",
+		Symptoms:       "Error messages related to security protocols.",
+		Severity:       "High",
+		PotentialRisks: "Data breaches and legal consequences.",
+		ProposedSol:    "Library replacement and implementation of 2FA.",
+		CostDelay:      "Increased risk of security breaches.",
+		Effort:         "4 weeks and €10,000.",
+		Dependencies:   "Completion of the security audit.",
+		Additional:     "Training for the development team.",
+	}
+
+	for _, r := range []Renderer{JSONRenderer{}, YAMLRenderer{}} {
+		t.Run(r.Name(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.Render(&buf, td); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), "schema_version") {
+				t.Errorf("Render() output lacks schema_version:\n%s", buf.String())
+			}
+			got, err := Decode(&buf, "record"+r.Extension())
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, td) {
+				t.Errorf("Decode() = %+v, want %+v", got, td)
+			}
+		})
+	}
+}
+
+// TestEmptyTemplateListsAllKeys checks that empty templates contain every key
+func TestEmptyTemplateListsAllKeys(t *testing.T) {
+	for _, r := range []Renderer{JSONRenderer{}, YAMLRenderer{}} {
+		t.Run(r.Name(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.Render(&buf, TechnicalDebt{Empty: true}); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, key := range recordKeys("json") {
+				if !strings.Contains(buf.String(), key) {
+					t.Errorf("template lacks key %q", key)
+				}
+			}
+			if _, err := Decode(&buf, "template"+r.Extension()); err != nil {
+				t.Errorf("Decode() of template error = %v", err)
+			}
+		})
+	}
+}
+
+// TestDecodeUnsupportedSchemaVersion checks that documents from newer schemas are rejected
+func TestDecodeUnsupportedSchemaVersion(t *testing.T) {
+	_, err := Decode(strings.NewReader(`{"schema_version": 99, "title": "Outdated Library"}`), "debt.json")
+	if err == nil {
+		t.Errorf("Decode() expected an error for schema_version 99")
+	}
+}
@@ -0,0 +1,117 @@
+package tdr
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Problem is an issue found while linting a repository
+type Problem struct {
+	// Path is the record file the problem was found in
+	Path string
+	// Message describes the problem
+	Message string
+}
+
+// String formats the problem as "file: message"
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", filepath.Base(p.Path), p.Message)
+}
+
+// Lint checks every record in the repository and returns the problems found.
+// An error is only returned if the repository itself cannot be read.
+func (r *Repository) Lint() ([]Problem, error) {
+	paths, err := r.RecordFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	report := func(path, format string, args ...any) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	links, err := r.Links()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]Record)
+	seen := make(map[string]string)
+	for _, path := range paths {
+		rec, err := LoadRecord(path)
+		if err != nil {
+			report(path, "cannot be parsed: %s", strings.TrimPrefix(err.Error(), path+": "))
+			continue
+		}
+		records[FormatID(fileNumber(path))] = rec
+
+		for _, rel := range rec.Relations {
+			if !rel.Kind.RefersToRecord() {
+				continue
+			}
+			if _, err := ParseID(rel.Target); err != nil {
+				report(path, "relation %q is not a record ID", rel.Target)
+			} else if _, ok := links(rel.Target); !ok {
+				report(path, "relation %s refers to a record that does not exist", rel.Target)
+			} else if number, _ := ParseID(rel.Target); number == fileNumber(path) {
+				report(path, "relation %s refers to the record itself", rel.Target)
+			}
+		}
+
+		if err := Validate(rec.TechnicalDebt); err != nil {
+			report(path, "%v", err)
+		}
+		if err := r.Schema.Validate(rec.TechnicalDebt); err != nil {
+			report(path, "%v", err)
+		}
+
+		switch {
+		case rec.ID == "":
+			report(path, "has no ID")
+		case rec.ID != FormatID(fileNumber(path)):
+			report(path, "ID %s does not match the file number", rec.ID)
+		}
+		if other, ok := seen[rec.ID]; ok && rec.ID != "" {
+			report(path, "ID %s is also used by %s", rec.ID, filepath.Base(other))
+		}
+		seen[rec.ID] = path
+	}
+
+	// Typed relations must be recorded on both ends
+	var loaded []Record
+	for _, path := range paths {
+		rec, ok := records[FormatID(fileNumber(path))]
+		if !ok {
+			continue
+		}
+		loaded = append(loaded, rec)
+		for _, rel := range rec.Relations {
+			kind, ok := rel.Kind.Inverse()
+			if !ok {
+				continue
+			}
+			number, err := ParseID(rel.Target)
+			if err != nil {
+				continue
+			}
+			target, ok := records[FormatID(number)]
+			if !ok {
+				continue
+			}
+			inverse := Relation{Kind: kind, Target: FormatID(fileNumber(path))}
+			if !slices.Contains(target.Relations, inverse) {
+				report(target.Path, "missing relation %q, the inverse of %s %s", inverse.String(), rec.ID, rel.String())
+			}
+		}
+	}
+
+	// Circular dependencies leave no record that can be resolved first
+	for _, cycle := range dependencyCycles(loaded) {
+		number, _ := ParseID(cycle[0])
+		report(records[FormatID(number)].Path, "dependency cycle %s", strings.Join(cycle, " -> "))
+	}
+	return problems, nil
+}
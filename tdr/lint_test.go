@@ -0,0 +1,108 @@
+package tdr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepository creates a repository holding one valid record per title
+func newTestRepository(t *testing.T, titles ...string) *Repository {
+	t.Helper()
+	repo, err := InitRepository(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("InitRepository() error = %v", err)
+	}
+	for _, title := range titles {
+		td := TechnicalDebt{
+			Title:   title,
+			Author:  "Jane Doe",
+			Version: "1.0.0",
+			Date:    "2024-04-15",
+			State:   "Identified",
+		}
+		if _, _, err := repo.Create(td); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	return repo
+}
+
+// TestLint checks the problems reported for broken repository records
+func TestLint(t *testing.T) {
+	repo := newTestRepository(t, "Outdated Library", "Missing Tests")
+
+	problems, err := repo.Lint()
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("Lint() = %v, want no problems", problems)
+	}
+
+	// A copy under another number keeps the original ID, an unparsable file and
+	// a record without author are added as well
+	rec, err := repo.Find("TDR-0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyPath := filepath.Join(repo.Dir, "0003-copy.md")
+	if err := os.WriteFile(copyPath, []byte(GenerateMarkdown(rec.TechnicalDebt)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo.Dir, "0004-garbage.md"), []byte("not a record"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rec.ID, rec.Author = "TDR-0005", ""
+	if err := os.WriteFile(filepath.Join(repo.Dir, "0005-no-author.md"), []byte(GenerateMarkdown(rec.TechnicalDebt)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = repo.Lint()
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	wants := []string{
+		"0003-copy.md: ID TDR-0001 does not match the file number",
+		"0003-copy.md: ID TDR-0001 is also used by 0001-outdated-library.md",
+		"0004-garbage.md: cannot be parsed",
+		"0005-no-author.md: Author is required",
+	}
+	if len(got) != len(wants) {
+		t.Fatalf("Lint() = %q, want %d problems", got, len(wants))
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(got[i], want) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got[i], want)
+		}
+	}
+}
+
+// TestLintCycle checks that circular depends-on chains are reported
+func TestLintCycle(t *testing.T) {
+	repo := newTestRepository(t, "Outdated Library", "Missing Tests")
+	for id, target := range map[string]string{"1": "TDR-0002", "2": "TDR-0001"} {
+		rec, err := repo.Find(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.Relations = append(rec.Relations, Relation{Kind: DependsOn, Target: target})
+		if err := repo.Save(rec); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	problems, err := repo.Lint()
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	want := "0001-outdated-library.md: dependency cycle TDR-0001 -> TDR-0002 -> TDR-0001"
+	if len(problems) != 1 || problems[0].String() != want {
+		t.Errorf("Lint() = %v, want %q", problems, want)
+	}
+}
@@ -0,0 +1,292 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownRenderer renders a record as Markdown
+type MarkdownRenderer struct {
+	// Links resolves relations to record files; without it relations link to "#"
+	Links LinkResolver
+}
+
+// Name implements Renderer
+func (MarkdownRenderer) Name() string { return "markdown" }
+
+// Extension implements Renderer
+func (MarkdownRenderer) Extension() string { return ".md" }
+
+// Render implements Renderer
+func (r MarkdownRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	_, err := io.WriteString(w, generateMarkdown(td, r.Links))
+	return err
+}
+
+// GenerateMarkdown generates the Markdown content
+func GenerateMarkdown(td TechnicalDebt) string {
+	return generateMarkdown(td, nil)
+}
+
+// generateMarkdown generates the Markdown content, linking relations to the
+// files returned by links
+func generateMarkdown(td TechnicalDebt, links LinkResolver) string {
+	relationsFormatted := markdownRelations(td.Relations, links)
+
+	if td.Empty {
+		return fmt.Sprintf(`# Technical Debt Record
+
+## Title
+
+**[Enter Title Here]**
+
+## Author
+
+[Enter Author Here]
+
+## Version
+
+**[Enter Version Here]**
+
+## Date
+
+[Enter Date Here]
+
+## State
+
+[Enter State Here]
+
+## Relations
+
+%s
+
+## Summary
+
+*A brief overview of the technical debt, explaining the problem in one or two sentences.*
+
+## Context
+
+*Provide the historical context and reasons why this technical debt exists.*
+
+## Impact
+
+### Technical Impact
+
+*Describe how the debt affects the system’s performance, scalability, or maintainability.*
+
+### Business Impact
+
+*Explain how the debt affects the business, such as increased risk, customer dissatisfaction, or slower feature delivery.*
+
+## Symptoms
+
+*List specific signs that indicate the presence of technical debt.*
+
+## Severity
+
+*[Enter Severity Here: Critical / High / Medium / Low]*
+
+## Potential Risks
+
+*Potential security vulnerabilities leading to data breaches.*
+
+## Proposed Solution
+
+*Describe how to resolve the technical debt.*
+
+## Cost of Delay
+
+*Explain the consequences of delaying the resolution of the technical debt.*
+
+## Effort to Resolve
+
+*Estimate the time, resources, and effort needed to address the debt.*
+
+## Dependencies
+
+*List any dependencies or blockers that need to be resolved before tackling the debt.*
+
+## Additional Notes
+
+*Any other relevant information or considerations.*
+`, relationsFormatted) + markdownFields(td.CustomFields, true)
+	}
+
+	// The ID section is only written for records that belong to a repository
+	idSection := ""
+	if td.ID != "" {
+		idSection = fmt.Sprintf("## ID\n\n%s\n\n", td.ID)
+	}
+
+	// Normal Markdown generation
+	return fmt.Sprintf(`# Technical Debt Record
+
+%s## Title
+
+%s
+
+## Author
+
+%s
+
+## Version
+
+%s
+
+## Date
+
+%s
+
+## State
+
+%s
+
+## Relations
+
+%s
+
+## Summary
+
+%s
+
+## Context
+
+%s
+
+## Impact
+
+### Technical Impact
+
+%s
+
+### Business Impact
+
+%s
+
+## Symptoms
+
+%s
+
+## Severity
+
+%s
+
+## Potential Risks
+
+%s
+
+## Proposed Solution
+
+%s
+
+## Cost of Delay
+
+%s
+
+## Effort to Resolve
+
+%s
+
+%s## Dependencies
+
+%s
+
+## Additional Notes
+
+%s
+`, idSection, escapeHeadings("**"+td.Title+"**"), escapeHeadings(td.Author), escapeHeadings(td.Version), td.Date, td.State, relationsFormatted,
+		escapeHeadings(td.Summary), escapeHeadings(td.Context), escapeHeadings(td.ImpactTech), escapeHeadings(td.ImpactBus),
+		escapeHeadings(td.Symptoms), td.Severity, escapeHeadings(td.PotentialRisks), escapeHeadings(td.ProposedSol),
+		escapeHeadings(td.CostDelay), escapeHeadings(td.Effort), markdownEstimates(td), escapeHeadings(td.Dependencies),
+		escapeHeadings(td.Additional)) +
+		markdownFields(td.CustomFields, false) + markdownHistory(td.History)
+}
+
+// markdownRelations renders the relations as lists grouped by kind. Untyped
+// relations come first without a heading; ADR references are not linked.
+func markdownRelations(relations []Relation, links LinkResolver) string {
+	if len(relations) == 0 {
+		return "None"
+	}
+	var groups []string
+	for _, group := range groupRelations(relations) {
+		var items []string
+		for _, rel := range group.Relations {
+			if !group.Kind.RefersToRecord() {
+				items = append(items, "- "+rel.Target)
+				continue
+			}
+			target := "#"
+			if name, ok := links.resolve(rel.Target); ok {
+				target = name + ".md"
+			}
+			items = append(items, fmt.Sprintf("- [%s](%s)", rel.Target, target))
+		}
+		list := strings.Join(items, "\n")
+		if group.Kind != RelatesTo {
+			list = fmt.Sprintf("**%s:**\n\n%s", group.Kind.Label(), list)
+		}
+		groups = append(groups, list)
+	}
+	return strings.Join(groups, "\n\n")
+}
+
+// markdownEstimates renders the estimates that are set as Markdown sections
+func markdownEstimates(td TechnicalDebt) string {
+	var b strings.Builder
+	for _, e := range estimates(&td) {
+		if *e.Value != 0 {
+			fmt.Fprintf(&b, "## %s\n\n%s\n\n", e.Heading, FormatNumber(*e.Value))
+		}
+	}
+	return b.String()
+}
+
+// markdownFields renders the custom fields as Markdown sections; the
+// placeholders of empty templates are set in italics
+func markdownFields(fields Fields, placeholder bool) string {
+	var b strings.Builder
+	for _, field := range fields {
+		value := field.Value
+		if placeholder && value != "" {
+			value = "*" + value + "*"
+		}
+		value = escapeHeadings(value)
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", field.Name, value)
+	}
+	return b.String()
+}
+
+// markdownHistory renders the state history as a table, or nothing if there is no history
+func markdownHistory(history []Transition) string {
+	if len(history) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n## History\n\n| Date | From | To | Actor |\n|------|------|----|-------|\n")
+	for _, t := range history {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", escapeTableCell(t.Date), escapeTableCell(t.From),
+			escapeTableCell(t.To), escapeTableCell(t.Actor))
+	}
+	return b.String()
+}
+
+// escapeTableCell escapes the characters that would break a Markdown table cell
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
+
+// escapeHeadings prefixes lines starting with '#', which would otherwise be read
+// back as section headings, with a backslash so that Markdown shows them as
+// text. Lines already starting with backslashes and '#' get one more, and
+// splitMarkdownSections removes one from both.
+func escapeHeadings(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimLeft(line, "\\"), "#") {
+			lines[i] = "\\" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,247 @@
+package tdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// markdownTitle is the document heading written by GenerateMarkdown
+const markdownTitle = "# Technical Debt Record"
+
+// ParseMarkdown reads a record in the layout produced by GenerateMarkdown.
+// Sections other than those of the built-in fields are read as custom fields.
+func ParseMarkdown(r io.Reader) (TechnicalDebt, error) {
+	var td TechnicalDebt
+
+	sections, err := splitMarkdownSections(r)
+	if err != nil {
+		return td, err
+	}
+
+	for _, section := range sections {
+		switch section.heading {
+		case "Title":
+			td.Title = strings.TrimSuffix(strings.TrimPrefix(section.body, "**"), "**")
+		case "Relations":
+			td.Relations, err = parseMarkdownRelations(section.body)
+			if err != nil {
+				return td, err
+			}
+		case "History":
+			td.History, err = parseMarkdownHistory(section.body)
+			if err != nil {
+				return td, err
+			}
+		case "Impact":
+			// The impact section only groups the technical and business impact
+		default:
+			if value := markdownEstimate(&td, section.heading); value != nil {
+				if *value, err = strconv.ParseFloat(section.body, 64); err != nil {
+					return td, fmt.Errorf("invalid %s %q", section.heading, section.body)
+				}
+				continue
+			}
+			field := markdownField(&td, section.heading)
+			if field == nil {
+				td.CustomFields.Set(section.heading, section.body)
+				continue
+			}
+			*field = section.body
+		}
+	}
+
+	// Records written by hand may use any case, e.g. "high"
+	normalizeSeverity(&td)
+	return td, nil
+}
+
+// markdownSection is a heading together with the text up to the next heading
+type markdownSection struct {
+	heading string
+	body    string
+}
+
+// splitMarkdownSections splits a Markdown record into its "##" and "###" sections
+func splitMarkdownSections(r io.Reader) ([]markdownSection, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var sections []markdownSection
+	var body []string
+	seenTitle := false
+
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].body = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case !seenTitle:
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if line != markdownTitle {
+				return nil, fmt.Errorf("missing %q heading", markdownTitle)
+			}
+			seenTitle = true
+		case strings.HasPrefix(line, "### "):
+			flush()
+			sections = append(sections, markdownSection{heading: strings.TrimSpace(line[4:])})
+		case strings.HasPrefix(line, "## "):
+			flush()
+			sections = append(sections, markdownSection{heading: strings.TrimSpace(line[3:])})
+		case strings.HasPrefix(strings.TrimLeft(line, "\\"), "#") && strings.HasPrefix(line, "\\"):
+			// A line of text escaped by escapeHeadings
+			body = append(body, line[1:])
+		default:
+			body = append(body, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Markdown: %w", err)
+	}
+	if !seenTitle {
+		return nil, fmt.Errorf("missing %q heading", markdownTitle)
+	}
+	flush()
+	return sections, nil
+}
+
+// parseMarkdownRelations reads the lists written by markdownRelations: "- [ID](link)"
+// items, grouped under "**Kind:**" lines for typed relations
+func parseMarkdownRelations(body string) ([]Relation, error) {
+	if body == "None" || body == "" {
+		return nil, nil
+	}
+	var relations []Relation
+	kind := RelatesTo
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if label, ok := strings.CutPrefix(line, "**"); ok && strings.HasSuffix(label, ":**") {
+			var err error
+			if kind, err = ParseRelationKind(strings.TrimSuffix(label, ":**")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			return nil, fmt.Errorf("invalid relation %q", line)
+		}
+		if strings.HasPrefix(item, "[") {
+			end := strings.Index(item, "](")
+			if end < 0 || !strings.HasSuffix(item, ")") {
+				return nil, fmt.Errorf("invalid relation link %q", item)
+			}
+			item = item[1:end]
+		}
+		relations = append(relations, Relation{Kind: kind, Target: strings.TrimSpace(item)})
+	}
+	return relations, nil
+}
+
+// parseMarkdownHistory reads the table written by markdownHistory
+func parseMarkdownHistory(body string) ([]Transition, error) {
+	var history []Transition
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		cells := splitTableRow(line)
+		if cells == nil || len(cells) != 4 {
+			return nil, fmt.Errorf("invalid history row %q, expected | Date | From | To | Actor |", line)
+		}
+		// Skip the header and the delimiter row
+		if i < 2 && (cells[0] == "Date" || strings.Trim(cells[0], "-: ") == "") {
+			continue
+		}
+		history = append(history, Transition{Date: cells[0], From: cells[1], To: cells[2], Actor: cells[3]})
+	}
+	return history, nil
+}
+
+// splitTableRow splits a Markdown table row into its unescaped cells, or returns nil if line is not a row
+func splitTableRow(line string) []string {
+	if !strings.HasPrefix(line, "|") || !strings.HasSuffix(line, "|") || len(line) < 2 {
+		return nil
+	}
+	line = line[1 : len(line)-1]
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownEstimate returns the numeric field written under the heading, or nil
+func markdownEstimate(td *TechnicalDebt, heading string) *float64 {
+	for _, e := range estimates(td) {
+		if e.Heading == heading {
+			return e.Value
+		}
+	}
+	return nil
+}
+
+// markdownField returns the field that holds the content of a Markdown section
+func markdownField(td *TechnicalDebt, heading string) *string {
+	switch heading {
+	case "ID":
+		return &td.ID
+	case "Author":
+		return &td.Author
+	case "Version":
+		return &td.Version
+	case "Date":
+		return &td.Date
+	case "State":
+		return &td.State
+	case "Summary":
+		return &td.Summary
+	case "Context":
+		return &td.Context
+	case "Technical Impact":
+		return &td.ImpactTech
+	case "Business Impact":
+		return &td.ImpactBus
+	case "Symptoms":
+		return &td.Symptoms
+	case "Severity":
+		return &td.Severity
+	case "Potential Risks":
+		return &td.PotentialRisks
+	case "Proposed Solution":
+		return &td.ProposedSol
+	case "Cost of Delay":
+		return &td.CostDelay
+	case "Effort to Resolve":
+		return &td.Effort
+	case "Dependencies":
+		return &td.Dependencies
+	case "Additional Notes":
+		return &td.Additional
+	}
+	return nil
+}
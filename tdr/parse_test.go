@@ -0,0 +1,151 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseMarkdownRoundTrip checks that parsing generated Markdown yields the original record
+func TestParseMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		td   TechnicalDebt
+	}{
+		{
+			name: "Full record",
+			td: TechnicalDebt{
+				Title:          "Outdated Library",
+				Author:         "Jane Doe",
+				Version:        "1.0.0",
+				Date:           "2024-04-15",
+				State:          "Analyzed",
+				Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
+				Summary:        "The library is outdated and causes security vulnerabilities.",
+				Context:        "Originally chosen for quick implementation.",
+				ImpactTech:     "Security risks and maintainability issues.",
+				ImpactBus:      "Impact on customer satisfaction.",
+				Symptoms:       "Error messages related to security protocols.",
+				Severity:       "High",
+				PotentialRisks: "Data breaches and legal consequences.",
+				ProposedSol:    "Library replacement and implementation of 2FA.",
+				CostDelay:      "Increased risk of security breaches.",
+				Effort:         "4 weeks and €10,000.",
+				Dependencies:   "Completion of the security audit.",
+				Additional:     "Training for the development team.",
+			},
+		},
+		{
+			name: "Repository record",
+			td: TechnicalDebt{
+				ID:        "TDR-0007",
+				Title:     "Outdated Library",
+				Author:    "Jane Doe",
+				Version:   "1.0.0",
+				Date:      "2024-04-15",
+				State:     "Approved",
+				Relations: []Relation{{Target: "TDR-0003"}},
+			},
+		},
+		{
+			name: "Required fields only",
+			td: TechnicalDebt{
+				Title:   "Outdated Library",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "2024-04-15",
+				State:   "Identified",
+			},
+		},
+		{
+			name: "Multi-line content",
+			td: TechnicalDebt{
+				Title:     "Outdated Library",
+				Author:    "Jane Doe",
+				Version:   "1.0.0",
+				Date:      "2024-04-15",
+				State:     "In Progress",
+				Relations: []Relation{{Target: "TDR-7"}},
+				Context:   "First paragraph.\n\nSecond paragraph\nwith a line break.",
+				Symptoms:  "- slow builds\n- flaky tests",
+			},
+		},
+		{
+			name: "Custom fields",
+			td: TechnicalDebt{
+				Title:        "Outdated Library",
+				Author:       "Jane Doe",
+				Version:      "1.0.0",
+				Date:         "2024-04-15",
+				State:        "Analyzed",
+				CustomFields: Fields{{"Component", "Backend"}, {"Jira Key", ""}, {"Due Date", "2024-06-30"}},
+				History:      []Transition{{Date: "2024-04-15", From: "Identified", To: "Analyzed", Actor: "Jane Doe"}},
+			},
+		},
+		{
+			name: "Heading-like content",
+			td: TechnicalDebt{
+				Title:        "Outdated Library",
+				Author:       "Jane Doe",
+				Version:      "1.0.0",
+				Date:         "2024-04-15",
+				State:        "Identified",
+				Summary:      "see below\n## State\n\nClosed",
+				Context:      "Intro\n\n### Background\n\nDetails",
+				Additional:   "# Notes\n\\## escaped\n\\\\### twice",
+				CustomFields: Fields{{"Component", "## History"}},
+			},
+		},
+		{
+			name: "Heading-like title",
+			td: TechnicalDebt{
+				Title:   "Legacy\n## State\n\nClosed",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "2024-04-15",
+				State:   "Identified",
+			},
+		},
+		{
+			name: "Title starting with a hash",
+			td: TechnicalDebt{
+				Title:   "# Legacy",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "2024-04-15",
+				State:   "Identified",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMarkdown(strings.NewReader(GenerateMarkdown(tt.td)))
+			if err != nil {
+				t.Fatalf("ParseMarkdown() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.td) {
+				t.Errorf("ParseMarkdown() = %+v, want %+v", got, tt.td)
+			}
+		})
+	}
+}
+
+// TestParseMarkdownErrors checks that malformed documents are rejected
+func TestParseMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Missing heading", input: "## Title\n\n**Outdated Library**\n"},
+		{name: "Invalid relation", input: "# Technical Debt Record\n\n## Relations\n\nTDR-102\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMarkdown(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ParseMarkdown() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package tdr
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/phpdave11/gofpdf"
+)
+
+// pdfFont is the family name the PDF text is set in, whichever font file is used
+const pdfFont = "TDR"
+
+// The bundled DejaVu Sans Condensed fonts cover Latin, Greek and Cyrillic
+// text, unlike the core PDF fonts, which only support Latin-1
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	pdfRegularFont []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	pdfBoldFont []byte
+)
+
+// Page layout in millimeters
+const (
+	pdfMargin       = 20
+	pdfTopMargin    = 25
+	pdfBottomMargin = 22
+	// pdfLineHeight is the line height of body text, set in 10.5 point
+	pdfLineHeight = 5.5
+)
+
+// Colors of the page furniture, matching the HTML style sheet
+const (
+	pdfTextColor   = "#1f2328"
+	pdfMutedColor  = "#59636e"
+	pdfBorderColor = "#d0d7de"
+)
+
+// PDFRenderer renders a record as a PDF document using the gofpdf library.
+// Text is set in a bundled Unicode font unless other TrueType fonts are given.
+type PDFRenderer struct {
+	// FontFile is a TrueType font file used instead of the bundled font
+	FontFile string
+	// BoldFontFile is the bold variant of FontFile, used for headings. It
+	// defaults to FontFile, or to the bundled bold font if FontFile is empty.
+	BoldFontFile string
+}
+
+// Name implements Renderer
+func (PDFRenderer) Name() string { return "pdf" }
+
+// Extension implements Renderer
+func (PDFRenderer) Extension() string { return ".pdf" }
+
+// Render implements Renderer
+func (r PDFRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	doc, err := r.newDocument()
+	if err != nil {
+		return err
+	}
+	doc.SetTitle(td.Title, true)
+	doc.SetAuthor(td.Author, true)
+	doc.header = pdfHeader(td)
+	doc.AddPage()
+	doc.record(td)
+
+	// Output the PDF
+	if err := doc.Output(w); err != nil {
+		return fmt.Errorf("error writing PDF: %w", err)
+	}
+	return nil
+}
+
+// pdfDocument is an A4 document with the page header and footer of technical
+// debt records
+type pdfDocument struct {
+	*gofpdf.Fpdf
+	// kind is shown on the left of the page header
+	kind string
+	// header is shown on the right of the page header of the following pages,
+	// e.g. "TDR-0007 · In Progress"
+	header string
+}
+
+// newDocument returns an empty document with the regular and bold fonts
+// registered as pdfFont
+func (r PDFRenderer) newDocument() (*pdfDocument, error) {
+	regular, bold := pdfRegularFont, pdfBoldFont
+	if r.FontFile != "" {
+		data, err := readTrueType(r.FontFile)
+		if err != nil {
+			return nil, err
+		}
+		regular, bold = data, data
+	}
+	if r.BoldFontFile != "" {
+		data, err := readTrueType(r.BoldFontFile)
+		if err != nil {
+			return nil, err
+		}
+		bold = data
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", regular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", bold)
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("error loading PDF font: %w", err)
+	}
+
+	doc := &pdfDocument{Fpdf: pdf, kind: "Technical Debt Record"}
+	pdf.SetMargins(pdfMargin, pdfTopMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfBottomMargin)
+	pdf.AliasNbPages("")
+	pdf.SetHeaderFuncMode(doc.pageHeader, true)
+	pdf.SetFooterFunc(doc.pageFooter)
+	pdf.SetFont(pdfFont, "", 10.5)
+	pdf.SetTextColor(rgb(pdfTextColor))
+	return doc, nil
+}
+
+// readTrueType reads a font file, rejecting files that are not TrueType fonts,
+// which gofpdf would only report when the document is written
+func readTrueType(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading PDF font: %w", err)
+	}
+	if !bytes.HasPrefix(data, []byte{0, 1, 0, 0}) && !bytes.HasPrefix(data, []byte("true")) {
+		return nil, fmt.Errorf("PDF font %s is not a TrueType (.ttf) font", path)
+	}
+	return data, nil
+}
+
+// pdfHeader returns the page header text of a record: its ID and state
+func pdfHeader(td TechnicalDebt) string {
+	var parts []string
+	for _, part := range []string{td.ID, td.State} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// pageHeader writes the header of every page, separated by a rule
+func (d *pdfDocument) pageHeader() {
+	width, _ := d.GetPageSize()
+	d.SetFont(pdfFont, "", 8)
+	d.SetTextColor(rgb(pdfMutedColor))
+	d.SetXY(pdfMargin, 12)
+	d.CellFormat(0, 5, strings.ToUpper(d.kind), "", 0, "L", false, 0, "")
+	d.SetX(pdfMargin)
+	d.CellFormat(0, 5, d.header, "", 0, "R", false, 0, "")
+	d.SetDrawColor(rgb(pdfBorderColor))
+	d.SetLineWidth(0.3)
+	d.Line(pdfMargin, 18, width-pdfMargin, 18)
+}
+
+// pageFooter writes "Page X of Y" at the bottom of every page
+func (d *pdfDocument) pageFooter() {
+	d.SetY(-15)
+	d.SetFont(pdfFont, "", 8)
+	d.SetTextColor(rgb(pdfMutedColor))
+	d.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", d.PageNo()), "", 0, "C", false, 0, "")
+}
+
+// record writes a record, starting at the current position: a title block
+// with the metadata, the severity banner and one section per field
+func (d *pdfDocument) record(td TechnicalDebt) {
+	title := td.Title
+	meta := [][2]string{
+		{"ID", td.ID},
+		{"Author", td.Author},
+		{"Version", td.Version},
+		{"Date", td.Date},
+		{"State", td.State},
+	}
+	if td.Empty {
+		title = "[Enter Title Here]"
+		meta = [][2]string{
+			{"Author", "[Enter Author Here]"},
+			{"Version", "[Enter Version Here]"},
+			{"Date", "[Enter Date Here]"},
+			{"State", "[Enter State Here]"},
+		}
+	}
+
+	// Title block
+	d.SetFont(pdfFont, "B", 20)
+	d.SetTextColor(rgb(pdfTextColor))
+	d.MultiCell(0, 9, title, "", "L", false)
+	d.Ln(3)
+	for _, field := range meta {
+		if field[1] == "" {
+			continue
+		}
+		d.SetFont(pdfFont, "B", 10)
+		d.SetTextColor(rgb(pdfMutedColor))
+		d.CellFormat(25, 6, field[0], "", 0, "L", false, 0, "")
+		d.SetFont(pdfFont, "", 10)
+		d.SetTextColor(rgb(pdfTextColor))
+		d.MultiCell(0, 6, field[1], "", "L", false)
+	}
+	d.Ln(4)
+	d.severityBanner(td)
+
+	for _, s := range pdfSections(td) {
+		d.section(s.title, s.content, td.Empty)
+	}
+}
+
+// pdfSection is a headed section of a record in a PDF
+type pdfSection struct {
+	title   string
+	content string
+}
+
+// pdfSections returns the sections of a record in the order of the other
+// renderers. The severity is shown in the banner instead.
+func pdfSections(td TechnicalDebt) []pdfSection {
+	sections := []pdfSection{
+		{"Relations", pdfRelations(td.Relations)},
+		{"Summary", td.Summary},
+		{"Context", td.Context},
+		{"Technical Impact", td.ImpactTech},
+		{"Business Impact", td.ImpactBus},
+		{"Symptoms", td.Symptoms},
+		{"Potential Risks", td.PotentialRisks},
+		{"Proposed Solution", td.ProposedSol},
+		{"Cost of Delay", td.CostDelay},
+		{"Effort to Resolve", td.Effort},
+	}
+	for _, e := range estimates(&td) {
+		if *e.Value != 0 {
+			sections = append(sections, pdfSection{e.Heading, FormatNumber(*e.Value)})
+		}
+	}
+	sections = append(sections,
+		pdfSection{"Dependencies", td.Dependencies},
+		pdfSection{"Additional Notes", td.Additional},
+	)
+	if td.Empty {
+		for i := range sections {
+			sections[i].content = htmlPlaceholders[sections[i].title]
+		}
+	}
+	// Custom fields follow the additional notes; empty templates hold their placeholders
+	for _, field := range td.CustomFields {
+		sections = append(sections, pdfSection{field.Name, field.Value})
+	}
+	if len(td.History) > 0 {
+		var lines []string
+		for _, t := range td.History {
+			lines = append(lines, fmt.Sprintf("%s  %s → %s  (%s)", t.Date, t.From, t.To, t.Actor))
+		}
+		sections = append(sections, pdfSection{"History", strings.Join(lines, "\n")})
+	}
+	return sections
+}
+
+// severityBanner writes a full-width bar in the color of the severity
+func (d *pdfDocument) severityBanner(td TechnicalDebt) {
+	text := "Severity: " + td.Severity
+	color, ok := severityColors[td.Severity]
+	switch {
+	case td.Empty:
+		text = "Severity: [Enter Severity Here: Critical / High / Medium / Low]"
+		color = unratedColor
+	case td.Severity == "":
+		text = "Severity: not rated"
+		color = unratedColor
+	case !ok:
+		color = unratedColor
+	}
+	d.SetFillColor(rgb(color))
+	d.SetTextColor(255, 255, 255)
+	d.SetFont(pdfFont, "B", 11)
+	d.SetCellMargin(3)
+	d.CellFormat(0, 9, text, "", 1, "L", true, 0, "")
+	d.SetCellMargin(1)
+	d.SetTextColor(rgb(pdfTextColor))
+	d.Ln(2)
+}
+
+// section writes a heading with a rule and the content as paragraphs, which
+// are wrapped to the page width. Empty content is shown as a dash; the hints
+// of empty templates are muted.
+func (d *pdfDocument) section(title, content string, hint bool) {
+	// Keep the heading together with the first lines of its content
+	_, height := d.GetPageSize()
+	if d.GetY()+10+3*pdfLineHeight > height-pdfBottomMargin {
+		d.AddPage()
+	}
+
+	width, _ := d.GetPageSize()
+	d.Ln(4)
+	d.SetFont(pdfFont, "B", 12)
+	d.SetTextColor(rgb(pdfTextColor))
+	d.CellFormat(0, 7, title, "", 1, "L", false, 0, "")
+	d.SetDrawColor(rgb(pdfBorderColor))
+	d.SetLineWidth(0.2)
+	d.Line(pdfMargin, d.GetY(), width-pdfMargin, d.GetY())
+	d.Ln(2)
+
+	d.SetFont(pdfFont, "", 10.5)
+	content = strings.TrimSpace(content)
+	if content == "" || hint {
+		d.SetTextColor(rgb(pdfMutedColor))
+	}
+	if content == "" {
+		content = "–"
+	}
+	for i, paragraph := range strings.Split(content, "\n\n") {
+		if i > 0 {
+			d.Ln(2)
+		}
+		d.MultiCell(0, pdfLineHeight, strings.TrimSpace(paragraph), "", "L", false)
+	}
+	d.SetTextColor(rgb(pdfTextColor))
+}
+
+// rgb converts a color such as "#d1242f" to its components
+func rgb(color string) (r, g, b int) {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+// pdfRelations lists the relations one kind per line, e.g. "Blocks: TDR-0005, TDR-0007"
+func pdfRelations(relations []Relation) string {
+	var lines []string
+	for _, group := range groupRelations(relations) {
+		var targets []string
+		for _, rel := range group.Relations {
+			targets = append(targets, rel.Target)
+		}
+		line := strings.Join(targets, ", ")
+		if group.Kind != RelatesTo {
+			line = group.Kind.Label() + ": " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package tdr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// estimate is one of the numeric fields used for prioritization and interest
+// tracking, with the heading it is rendered under
+type estimate struct {
+	Heading string
+	Value   *float64
+}
+
+// estimates returns the numeric fields of td in rendering order. The effort is
+// estimated in person-days or story points; cost of delay and risk reduction
+// may use any unit, such as money or relative points, as long as all records
+// use the same. The interest rate is the number of hours lost per week.
+func estimates(td *TechnicalDebt) []estimate {
+	return []estimate{
+		{"Effort Estimate", &td.EffortEstimate},
+		{"Cost of Delay per Week", &td.CostOfDelayWeekly},
+		{"Risk Reduction", &td.RiskReduction},
+		{"Interest Rate (Hours per Week)", &td.InterestRate},
+	}
+}
+
+// FormatNumber formats an estimate without trailing zeros, e.g. 2.5 or 8
+func FormatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// validateEstimates rejects negative and non-finite estimates
+func validateEstimates(td TechnicalDebt) error {
+	for _, e := range estimates(&td) {
+		if v := *e.Value; v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%s %s is invalid, use a number of 0 or more", e.Heading, FormatNumber(v))
+		}
+	}
+	return nil
+}
+
+// WSJF returns the Weighted Shortest Job First score of the record: its cost
+// of delay per week plus its risk reduction, divided by its effort estimate.
+// It returns false if the record has no effort estimate.
+func (td TechnicalDebt) WSJF() (float64, bool) {
+	if td.EffortEstimate <= 0 {
+		return 0, false
+	}
+	return (td.CostOfDelayWeekly + td.RiskReduction) / td.EffortEstimate, true
+}
+
+// Priority is a record together with its WSJF score
+type Priority struct {
+	Record
+	// Score is the WSJF score, valid only if Scored is set
+	Score  float64
+	Scored bool
+}
+
+// Prioritize ranks the records by descending WSJF score. Records without an
+// effort estimate cannot be scored and come last, in their original order.
+func Prioritize(records []Record) []Priority {
+	priorities := make([]Priority, len(records))
+	for i, rec := range records {
+		score, ok := rec.WSJF()
+		priorities[i] = Priority{Record: rec, Score: score, Scored: ok}
+	}
+	sort.SliceStable(priorities, func(i, j int) bool {
+		a, b := priorities[i], priorities[j]
+		if a.Scored != b.Scored {
+			return a.Scored
+		}
+		return a.Score > b.Score
+	})
+	return priorities
+}
@@ -0,0 +1,60 @@
+package tdr
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPrioritize checks the WSJF ranking, with unscored records last
+func TestPrioritize(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001"}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", EffortEstimate: 4, CostOfDelayWeekly: 2}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0003", EffortEstimate: 2, CostOfDelayWeekly: 3, RiskReduction: 1}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0004", CostOfDelayWeekly: 100}},
+	}
+	var got []string
+	for _, p := range Prioritize(records) {
+		got = append(got, p.ID)
+	}
+	if want := "TDR-0003 TDR-0002 TDR-0001 TDR-0004"; strings.Join(got, " ") != want {
+		t.Errorf("Prioritize() order = %v, want %s", got, want)
+	}
+
+	if score, ok := records[2].WSJF(); !ok || score != 2 {
+		t.Errorf("WSJF() = %v, %v, want 2, true", score, ok)
+	}
+}
+
+// TestEstimates checks that estimates are validated and survive a Markdown round trip
+func TestEstimates(t *testing.T) {
+	td := TechnicalDebt{
+		Title:             "Outdated Library",
+		Author:            "Jane Doe",
+		Version:           "1.0.0",
+		Date:              "2024-04-15",
+		State:             "Identified",
+		EffortEstimate:    5,
+		CostOfDelayWeekly: 12.5,
+	}
+	if err := Validate(td); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	markdown := GenerateMarkdown(td)
+	if !strings.Contains(markdown, "## Effort Estimate\n\n5\n\n## Cost of Delay per Week\n\n12.5\n\n## Dependencies") {
+		t.Errorf("GenerateMarkdown() does not contain the estimates:\n%s", markdown)
+	}
+	parsed, err := ParseMarkdown(strings.NewReader(markdown))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if parsed.EffortEstimate != 5 || parsed.CostOfDelayWeekly != 12.5 || parsed.RiskReduction != 0 {
+		t.Errorf("ParseMarkdown() estimates = %v, %v, %v", parsed.EffortEstimate, parsed.CostOfDelayWeekly, parsed.RiskReduction)
+	}
+
+	td.RiskReduction = -1
+	if err := Validate(td); err == nil {
+		t.Error("Validate() accepted a negative risk reduction")
+	}
+}
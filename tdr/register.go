@@ -0,0 +1,168 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteRegister writes the records as one PDF debt register for printing: a
+// cover page, a table of contents linking to the pages of the records, a
+// summary table by state and severity, and every record on pages of its own,
+// laid out as by Render.
+func (r PDFRenderer) WriteRegister(w io.Writer, records []Record, now time.Time) error {
+	// The table of contents lists the pages the sections start on, which are
+	// only known once the document is laid out, so it is laid out twice
+	_, pages, err := r.register(records, now, nil)
+	if err != nil {
+		return err
+	}
+	doc, _, err := r.register(records, now, pages)
+	if err != nil {
+		return err
+	}
+	if err := doc.Output(w); err != nil {
+		return fmt.Errorf("error writing PDF: %w", err)
+	}
+	return nil
+}
+
+// registerEntry is a line of the table of contents of a register
+type registerEntry struct {
+	id    string
+	title string
+}
+
+// register lays out the register with the given start pages of the summary
+// and the records in the table of contents, and returns the document and
+// the actual start pages
+func (r PDFRenderer) register(records []Record, now time.Time, pages []int) (*pdfDocument, []int, error) {
+	doc, err := r.newDocument()
+	if err != nil {
+		return nil, nil, err
+	}
+	doc.kind = "Technical Debt Register"
+	doc.SetTitle("Technical Debt Register", true)
+
+	entries := []registerEntry{{title: "Summary by State and Severity"}}
+	open := 0
+	for _, rec := range records {
+		entries = append(entries, registerEntry{rec.ID, rec.Title})
+		if !Settled(rec.State) {
+			open++
+		}
+	}
+	links := make([]int, len(entries))
+	for i := range links {
+		links[i] = doc.AddLink()
+	}
+
+	// Cover page
+	doc.AddPage()
+	doc.SetY(100)
+	doc.SetFont(pdfFont, "B", 28)
+	doc.MultiCell(0, 12, "Technical Debt Register", "", "C", false)
+	doc.Ln(6)
+	doc.SetFont(pdfFont, "", 12)
+	doc.SetTextColor(rgb(pdfMutedColor))
+	doc.MultiCell(0, 7, fmt.Sprintf("%d records, %d of them open", len(records), open), "", "C", false)
+	doc.MultiCell(0, 7, now.Format(DateFormat), "", "C", false)
+	doc.SetTextColor(rgb(pdfTextColor))
+
+	// Table of contents, each line linking to its section
+	doc.AddPage()
+	doc.Bookmark("Contents", 0, -1)
+	doc.heading("Contents")
+	width, _ := doc.GetPageSize()
+	pageWidth := 15.0
+	titleWidth := width - 2*pdfMargin - pageWidth
+	for i, entry := range entries {
+		text := entry.title
+		if entry.id != "" {
+			text = entry.id + "  " + entry.title
+		}
+		page := ""
+		if i < len(pages) {
+			page = fmt.Sprint(pages[i])
+		}
+		doc.SetFont(pdfFont, "", 10.5)
+		doc.CellFormat(titleWidth, 7, doc.truncate(text, titleWidth-2), "", 0, "L", false, links[i], "")
+		doc.CellFormat(pageWidth, 7, page, "", 1, "R", false, links[i], "")
+	}
+
+	// Summary by state and severity
+	actual := make([]int, len(entries))
+	doc.AddPage()
+	actual[0] = doc.PageNo()
+	doc.SetLink(links[0], -1, actual[0])
+	doc.Bookmark("Summary", 0, -1)
+	doc.heading(entries[0].title)
+	tds := make([]TechnicalDebt, len(records))
+	for i, rec := range records {
+		tds[i] = rec.TechnicalDebt
+	}
+	doc.table(summaryRows(tds))
+
+	// One record after the other, each starting on a new page
+	for i, rec := range records {
+		doc.header = pdfHeader(rec.TechnicalDebt)
+		doc.AddPage()
+		actual[i+1] = doc.PageNo()
+		doc.SetLink(links[i+1], -1, actual[i+1])
+		doc.Bookmark(strings.TrimSpace(rec.ID+" "+rec.Title), 0, -1)
+		doc.record(rec.TechnicalDebt)
+	}
+	if err := doc.Error(); err != nil {
+		return nil, nil, fmt.Errorf("error writing PDF: %w", err)
+	}
+	return doc, actual, nil
+}
+
+// heading writes the heading of a register page
+func (d *pdfDocument) heading(text string) {
+	d.SetFont(pdfFont, "B", 18)
+	d.SetTextColor(rgb(pdfTextColor))
+	d.MultiCell(0, 9, text, "", "L", false)
+	d.Ln(4)
+}
+
+// table writes rows of cells as a table with a bold header row and total row;
+// the first column holds the row names, the other columns numbers
+func (d *pdfDocument) table(rows [][]any) {
+	if len(rows) == 0 {
+		return
+	}
+	width, _ := d.GetPageSize()
+	first := 32.0
+	other := (width - 2*pdfMargin - first) / float64(len(rows[0])-1)
+	d.SetDrawColor(rgb(pdfBorderColor))
+	d.SetLineWidth(0.2)
+	for i, row := range rows {
+		style := ""
+		if i == 0 || i == len(rows)-1 {
+			style = "B"
+		}
+		d.SetFont(pdfFont, style, 10)
+		for j, cell := range row {
+			w, align := other, "R"
+			if j == 0 {
+				w, align = first, "L"
+			}
+			d.CellFormat(w, 7, fmt.Sprint(cell), "B", 0, align, false, 0, "")
+		}
+		d.Ln(-1)
+	}
+}
+
+// truncate shortens text with an ellipsis so that it fits into width
+func (d *pdfDocument) truncate(text string, width float64) string {
+	if d.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && d.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,44 @@
+package tdr
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteRegister checks the page layout of the register and that the table
+// of contents points to the pages the sections start on
+func TestWriteRegister(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", State: "Identified", Severity: "High",
+			Summary: strings.Repeat("The library is outdated. ", 400)}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", Title: "Missing Tests", State: "Closed"}},
+	}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, first, err := PDFRenderer{}.register(records, now, nil)
+	if err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+	_, pages, err := PDFRenderer{}.register(records, now, first)
+	if err != nil {
+		t.Fatalf("register() error = %v", err)
+	}
+	if !slices.Equal(pages, first) {
+		t.Errorf("pages changed from %v to %v once the table of contents was filled in", first, pages)
+	}
+	// Cover and contents come first, the long first record takes several pages
+	if pages[0] != 3 || pages[1] != 4 || pages[2] <= pages[1]+1 {
+		t.Errorf("sections start on pages %v", pages)
+	}
+
+	var buf bytes.Buffer
+	if err := (PDFRenderer{}).WriteRegister(&buf, records, now); err != nil {
+		t.Fatalf("WriteRegister() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("WriteRegister() did not write a PDF document")
+	}
+}
@@ -0,0 +1,184 @@
+package tdr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelationKind is the meaning of a relation between records
+type RelationKind string
+
+// The relation kinds. Every kind except RelatesTo and CausedBy has an inverse,
+// which the repository adds to the target record automatically.
+const (
+	// RelatesTo is an untyped relation, written as just the target ID
+	RelatesTo    RelationKind = ""
+	Blocks       RelationKind = "blocks"
+	BlockedBy    RelationKind = "blocked-by"
+	DependsOn    RelationKind = "depends-on"
+	RequiredBy   RelationKind = "required-by"
+	Supersedes   RelationKind = "supersedes"
+	SupersededBy RelationKind = "superseded-by"
+	Duplicates   RelationKind = "duplicates"
+	DuplicatedBy RelationKind = "duplicated-by"
+	// CausedBy refers to the Architecture Decision Record that caused the debt
+	CausedBy RelationKind = "caused-by"
+)
+
+// RelationKinds lists the relation kinds in the order renderers group them
+var RelationKinds = []RelationKind{
+	RelatesTo,
+	Blocks,
+	BlockedBy,
+	DependsOn,
+	RequiredBy,
+	Supersedes,
+	SupersededBy,
+	Duplicates,
+	DuplicatedBy,
+	CausedBy,
+}
+
+// relationLabels holds the headings used when rendering relations grouped by kind
+var relationLabels = map[RelationKind]string{
+	RelatesTo:    "Related to",
+	Blocks:       "Blocks",
+	BlockedBy:    "Blocked by",
+	DependsOn:    "Depends on",
+	RequiredBy:   "Required by",
+	Supersedes:   "Supersedes",
+	SupersededBy: "Superseded by",
+	Duplicates:   "Duplicates",
+	DuplicatedBy: "Duplicated by",
+	CausedBy:     "Caused by ADR",
+}
+
+// relationInverses maps each kind to the kind shown on the target record
+var relationInverses = map[RelationKind]RelationKind{
+	Blocks:       BlockedBy,
+	BlockedBy:    Blocks,
+	DependsOn:    RequiredBy,
+	RequiredBy:   DependsOn,
+	Supersedes:   SupersededBy,
+	SupersededBy: Supersedes,
+	Duplicates:   DuplicatedBy,
+	DuplicatedBy: Duplicates,
+}
+
+// Label returns the heading used for the kind, e.g. "Blocked by"
+func (k RelationKind) Label() string {
+	return relationLabels[k]
+}
+
+// Inverse returns the kind shown on the target of a relation of this kind,
+// and false if the kind has no inverse
+func (k RelationKind) Inverse() (RelationKind, bool) {
+	inverse, ok := relationInverses[k]
+	return inverse, ok
+}
+
+// RefersToRecord reports whether the target of a relation of this kind is a
+// technical debt record, as opposed to an ADR
+func (k RelationKind) RefersToRecord() bool {
+	return k != CausedBy
+}
+
+// ParseRelationKind returns the kind for a keyword such as "blocks",
+// "depends-on", "depends on" or a label such as "Blocked by"
+func ParseRelationKind(s string) (RelationKind, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(s, "-", " ")), "-"))
+	for _, kind := range RelationKinds {
+		if normalized == string(kind) || normalized == strings.ToLower(strings.ReplaceAll(kind.Label(), " ", "-")) {
+			return kind, nil
+		}
+	}
+	if normalized == "relates-to" {
+		return RelatesTo, nil
+	}
+	var keywords []string
+	for _, kind := range RelationKinds[1:] {
+		keywords = append(keywords, string(kind))
+	}
+	return "", fmt.Errorf("unknown relation kind %q, known kinds are: %s", s, strings.Join(keywords, ", "))
+}
+
+// Relation links a record to another record or, for CausedBy, to an ADR
+type Relation struct {
+	Kind   RelationKind
+	Target string
+}
+
+// ParseRelation reads a relation written as "[KIND] TARGET", e.g. "TDR-0005",
+// "blocks TDR-0005", "depends on TDR-0005" or "caused-by ADR-0012"
+func ParseRelation(s string) (Relation, error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 0:
+		return Relation{}, fmt.Errorf("empty relation")
+	case 1:
+		return Relation{Target: fields[0]}, nil
+	}
+	kind, err := ParseRelationKind(strings.Join(fields[:len(fields)-1], " "))
+	if err != nil {
+		return Relation{}, err
+	}
+	return Relation{Kind: kind, Target: fields[len(fields)-1]}, nil
+}
+
+// String returns the relation in the form read by ParseRelation
+func (r Relation) String() string {
+	if r.Kind == RelatesTo {
+		return r.Target
+	}
+	return string(r.Kind) + " " + r.Target
+}
+
+// MarshalText implements encoding.TextMarshaler, so relations are plain strings in JSON and YAML
+func (r Relation) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (r *Relation) UnmarshalText(text []byte) error {
+	rel, err := ParseRelation(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rel
+	return nil
+}
+
+// relationGroup is the relations of one kind, as rendered under a common heading
+type relationGroup struct {
+	Kind      RelationKind
+	Relations []Relation
+}
+
+// groupRelations groups relations by kind in the order of RelationKinds
+func groupRelations(relations []Relation) []relationGroup {
+	var groups []relationGroup
+	for _, kind := range RelationKinds {
+		var group []Relation
+		for _, rel := range relations {
+			if rel.Kind == kind {
+				group = append(group, rel)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, relationGroup{Kind: kind, Relations: group})
+		}
+	}
+	return groups
+}
+
+// joinRelations writes the relations grouped by kind as one comma-separated
+// list, e.g. "TDR-0001, blocks TDR-0005", as used in spreadsheet cells
+func joinRelations(relations []Relation) string {
+	var items []string
+	for _, group := range groupRelations(relations) {
+		for _, rel := range group.Relations {
+			items = append(items, rel.String())
+		}
+	}
+	return strings.Join(items, ", ")
+}
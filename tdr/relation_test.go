@@ -0,0 +1,87 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseRelation checks the accepted relation forms and their inverses
+func TestParseRelation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Relation
+		inverse RelationKind
+	}{
+		{"TDR-0005", Relation{Target: "TDR-0005"}, ""},
+		{"blocks TDR-0005", Relation{Kind: Blocks, Target: "TDR-0005"}, BlockedBy},
+		{"Depends on 5", Relation{Kind: DependsOn, Target: "5"}, RequiredBy},
+		{"superseded-by TDR-2", Relation{Kind: SupersededBy, Target: "TDR-2"}, Supersedes},
+		{"duplicates TDR-0003", Relation{Kind: Duplicates, Target: "TDR-0003"}, DuplicatedBy},
+		{"Caused by ADR ADR-0012", Relation{Kind: CausedBy, Target: "ADR-0012"}, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseRelation(tt.input)
+		if err != nil {
+			t.Errorf("ParseRelation(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRelation(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+		if inverse, _ := got.Kind.Inverse(); inverse != tt.inverse {
+			t.Errorf("%q inverse = %q, want %q", tt.input, inverse, tt.inverse)
+		}
+	}
+
+	for _, input := range []string{"", "fixes TDR-0005"} {
+		if _, err := ParseRelation(input); err == nil {
+			t.Errorf("ParseRelation(%q) succeeded, want error", input)
+		}
+	}
+}
+
+// TestRelationsRoundTrip checks that grouped relations survive Markdown and JSON
+func TestRelationsRoundTrip(t *testing.T) {
+	td := TechnicalDebt{
+		Title:   "Legacy Authentication Module",
+		Author:  "Jane Doe",
+		Version: "1.0.0",
+		Date:    "2024-04-15",
+		State:   "Identified",
+		Relations: []Relation{
+			{Kind: Blocks, Target: "TDR-0005"},
+			{Target: "TDR-0002"},
+			{Kind: CausedBy, Target: "ADR-0012"},
+			{Kind: Blocks, Target: "TDR-0007"},
+		},
+	}
+	grouped := []Relation{td.Relations[1], td.Relations[0], td.Relations[3], td.Relations[2]}
+
+	markdown := GenerateMarkdown(td)
+	if !strings.Contains(markdown, "- [TDR-0002](#)\n\n**Blocks:**\n\n- [TDR-0005](#)\n- [TDR-0007](#)\n\n**Caused by ADR:**\n\n- ADR-0012\n") {
+		t.Errorf("GenerateMarkdown() relations not grouped by kind:\n%s", markdown)
+	}
+	parsed, err := ParseMarkdown(strings.NewReader(markdown))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Relations, grouped) {
+		t.Errorf("ParseMarkdown() relations = %v, want %v", parsed.Relations, grouped)
+	}
+
+	var buf strings.Builder
+	if err := (JSONRenderer{}).Render(&buf, td); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"blocks TDR-0005"`) {
+		t.Errorf("JSON does not write relations as strings:\n%s", buf.String())
+	}
+	decoded, err := DecodeJSON(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Relations, td.Relations) {
+		t.Errorf("DecodeJSON() relations = %v, want %v", decoded.Relations, td.Relations)
+	}
+}
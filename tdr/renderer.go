@@ -0,0 +1,104 @@
+package tdr
+
+import (
+	"io"
+	"strings"
+)
+
+// Renderer writes a technical debt record in a specific output format
+type Renderer interface {
+	// Name returns the format name used to select the renderer, e.g. "markdown"
+	Name() string
+	// Extension returns the file extension including the leading dot, e.g. ".md"
+	Extension() string
+	// Render writes the record to w
+	Render(w io.Writer, td TechnicalDebt) error
+}
+
+// LinkResolver returns the file name, without extension, of the record with
+// the given ID, e.g. "0007-outdated-library" for "TDR-0007". It reports false
+// for unknown records.
+type LinkResolver func(id string) (string, bool)
+
+// resolve calls the resolver, treating a nil resolver as knowing no records
+func (links LinkResolver) resolve(id string) (string, bool) {
+	if links == nil {
+		return "", false
+	}
+	return links(id)
+}
+
+// WithLinks returns a copy of the renderer that links relations using links.
+// Renderers that do not produce links are returned unchanged.
+func WithLinks(r Renderer, links LinkResolver) Renderer {
+	switch r := r.(type) {
+	case MarkdownRenderer:
+		r.Links = links
+		return r
+	case HTMLRenderer:
+		r.Links = links
+		return r
+	case TemplateRenderer:
+		r.Links = links
+		return r
+	}
+	return r
+}
+
+// WithSchema returns a copy of the renderer that types the custom fields as
+// declared by the schema. Renderers that write the custom fields as text are
+// returned unchanged.
+func WithSchema(r Renderer, schema *Schema) Renderer {
+	switch r := r.(type) {
+	case ExcelRenderer:
+		r.Schema = schema
+		return r
+	case CSVRenderer:
+		r.Schema = schema
+		return r
+	}
+	return r
+}
+
+// renderers holds the registered renderers in the order they are listed to users
+var renderers = []Renderer{
+	MarkdownRenderer{},
+	ASCIIRenderer{},
+	PDFRenderer{},
+	ExcelRenderer{},
+	CSVRenderer{},
+	HTMLRenderer{},
+	JSONRenderer{},
+	YAMLRenderer{},
+}
+
+// Register adds a renderer, replacing any renderer registered under the same name
+func Register(r Renderer) {
+	for i, existing := range renderers {
+		if existing.Name() == r.Name() {
+			renderers[i] = r
+			return
+		}
+	}
+	renderers = append(renderers, r)
+}
+
+// Lookup returns the renderer registered for the given format name
+func Lookup(name string) (Renderer, bool) {
+	name = strings.ToLower(name)
+	for _, r := range renderers {
+		if r.Name() == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Formats returns the names of all registered renderers
+func Formats() []string {
+	names := make([]string, 0, len(renderers))
+	for _, r := range renderers {
+		names = append(names, r.Name())
+	}
+	return names
+}
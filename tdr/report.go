@@ -0,0 +1,264 @@
+package tdr
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportFormats lists the formats WriteReport supports
+var ReportFormats = []string{"markdown", "html", "json"}
+
+// Portfolio is the aggregate view of all records in a repository
+type Portfolio struct {
+	// Date is the day the portfolio was computed, formatted as DateFormat
+	Date    string `json:"date"`
+	Records int    `json:"records"`
+	// Open counts the records that are not settled, see Settled
+	Open       int           `json:"open"`
+	ByState    []Count       `json:"by_state"`
+	BySeverity []Count       `json:"by_severity"`
+	OldestOpen []ReportEntry `json:"oldest_open"`
+	// WithoutSolution lists the open records without a proposed solution
+	WithoutSolution []ReportEntry `json:"without_solution"`
+	// TotalEffort sums the effort estimates of the open records
+	TotalEffort float64 `json:"total_effort"`
+	// Unestimated counts the open records without an effort estimate
+	Unestimated int `json:"unestimated"`
+}
+
+// Count is the number of records with a state or severity
+type Count struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+}
+
+// ReportEntry is a record listed in a portfolio
+type ReportEntry struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Severity string `json:"severity,omitempty"`
+	Date     string `json:"date"`
+	// AgeDays is the number of days since Date
+	AgeDays int `json:"age_days"`
+}
+
+// NewPortfolio aggregates the records as of now. It lists up to oldest open
+// records by age; every state and severity is counted, even with no records.
+func NewPortfolio(records []Record, now time.Time, oldest int) Portfolio {
+	today, _ := time.Parse(DateFormat, now.Format(DateFormat))
+	p := Portfolio{
+		Date:            today.Format(DateFormat),
+		Records:         len(records),
+		OldestOpen:      []ReportEntry{},
+		WithoutSolution: []ReportEntry{},
+	}
+
+	states := make(map[string]int)
+	severities := make(map[string]int)
+	var open []ReportEntry
+	for _, rec := range records {
+		states[rec.State]++
+		severity := rec.Severity
+		if severity == "" {
+			severity = "None"
+		}
+		severities[severity]++
+		if Settled(rec.State) {
+			continue
+		}
+
+		entry := ReportEntry{ID: rec.ID, Title: rec.Title, State: rec.State, Severity: rec.Severity, Date: rec.Date}
+		if date, err := time.Parse(DateFormat, rec.Date); err == nil {
+			entry.AgeDays = int(today.Sub(date).Hours() / 24)
+		}
+		open = append(open, entry)
+		if strings.TrimSpace(rec.ProposedSol) == "" {
+			p.WithoutSolution = append(p.WithoutSolution, entry)
+		}
+		if rec.EffortEstimate > 0 {
+			p.TotalEffort += rec.EffortEstimate
+		} else {
+			p.Unestimated++
+		}
+	}
+	p.Open = len(open)
+	p.ByState = counts(states, AllowedStates)
+	p.BySeverity = counts(severities, append(append([]string(nil), AllowedSeverities...), "None"))
+
+	sort.SliceStable(open, func(i, j int) bool { return open[i].AgeDays > open[j].AgeDays })
+	if len(open) > oldest {
+		open = open[:oldest]
+	}
+	p.OldestOpen = append(p.OldestOpen, open...)
+	return p
+}
+
+// counts returns the counts in the given order of names, followed by any other
+// names, such as invalid states, in alphabetical order
+func counts(m map[string]int, order []string) []Count {
+	var result []Count
+	for _, name := range order {
+		result = append(result, Count{Name: name, Records: m[name]})
+		delete(m, name)
+	}
+	var rest []string
+	for name := range m {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		result = append(result, Count{Name: name, Records: m[name]})
+	}
+	return result
+}
+
+// WriteReport writes the portfolio in one of ReportFormats
+func WriteReport(w io.Writer, format string, p Portfolio) error {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		_, err := io.WriteString(w, markdownReport(p))
+		return err
+	case "html":
+		if err := reportTemplate.Execute(w, p); err != nil {
+			return fmt.Errorf("error writing HTML: %w", err)
+		}
+		return nil
+	case "json":
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding JSON: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported report format %q, supported formats are: %s", format, strings.Join(ReportFormats, ", "))
+}
+
+// markdownReport renders the portfolio as Markdown tables
+func markdownReport(p Portfolio) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Technical Debt Portfolio\n\nGenerated on %s from %d records, %d of them open.\n", p.Date, p.Records, p.Open)
+
+	for _, section := range []struct {
+		title, column string
+		counts        []Count
+	}{
+		{"Records by State", "State", p.ByState},
+		{"Records by Severity", "Severity", p.BySeverity},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n| %s | Records |\n|------|---------|\n", section.title, section.column)
+		for _, c := range section.counts {
+			fmt.Fprintf(&b, "| %s | %d |\n", escapeTableCell(c.Name), c.Records)
+		}
+	}
+
+	for _, section := range []struct {
+		title   string
+		entries []ReportEntry
+	}{
+		{"Oldest Open Records", p.OldestOpen},
+		{"Open Records without Proposed Solution", p.WithoutSolution},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		if len(section.entries) == 0 {
+			b.WriteString("None\n")
+			continue
+		}
+		b.WriteString("| ID | Title | State | Severity | Date | Age (Days) |\n|----|-------|-------|----------|------|------------|\n")
+		for _, e := range section.entries {
+			severity := e.Severity
+			if severity == "" {
+				severity = "-"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d |\n", escapeTableCell(e.ID), escapeTableCell(e.Title),
+				escapeTableCell(e.State), escapeTableCell(severity), escapeTableCell(e.Date), e.AgeDays)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Estimated Effort\n\nThe open records add up to an estimated effort of %s.", FormatNumber(p.TotalEffort))
+	if p.Unestimated > 0 {
+		fmt.Fprintf(&b, " %d open record(s) have no effort estimate.", p.Unestimated)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"badgeClass":   badgeClass,
+	"formatNumber": FormatNumber,
+	"entriesSection": func(title string, entries []ReportEntry) any {
+		return struct {
+			Title   string
+			Entries []ReportEntry
+		}{title, entries}
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Technical Debt Portfolio</title>
+<style>
+` + htmlStyle + `</style>
+</head>
+<body>
+<header>
+  <p class="kind">Technical Debt Portfolio</p>
+  <h1>{{.Open}} open of {{.Records}} records</h1>
+  <dl class="meta">
+    <dt>Date</dt><dd>{{.Date}}</dd>
+    <dt>Estimated effort</dt><dd>{{formatNumber .TotalEffort}}{{if .Unestimated}} ({{.Unestimated}} open record(s) without estimate){{end}}</dd>
+  </dl>
+</header>
+<main>
+<section>
+<h2>Records by State</h2>
+<table>
+<thead><tr><th>State</th><th>Records</th></tr></thead>
+<tbody>
+{{- range .ByState}}
+<tr><td><span class="badge state {{badgeClass .Name}}">{{.Name}}</span></td><td>{{.Records}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</section>
+<section>
+<h2>Records by Severity</h2>
+<table>
+<thead><tr><th>Severity</th><th>Records</th></tr></thead>
+<tbody>
+{{- range .BySeverity}}
+<tr><td><span class="badge severity {{badgeClass .Name}}">{{.Name}}</span></td><td>{{.Records}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</section>
+{{- template "entries" (entriesSection "Oldest Open Records" .OldestOpen)}}
+{{- template "entries" (entriesSection "Open Records without Proposed Solution" .WithoutSolution)}}
+</main>
+</body>
+</html>
+{{define "entries"}}
+<section>
+<h2>{{.Title}}</h2>
+{{- if .Entries}}
+<table>
+<thead><tr><th>ID</th><th>Title</th><th>State</th><th>Severity</th><th>Date</th><th>Age (Days)</th></tr></thead>
+<tbody>
+{{- range .Entries}}
+<tr><td>{{.ID}}</td><td>{{.Title}}</td><td><span class="badge state {{badgeClass .State}}">{{.State}}</span></td><td>{{if .Severity}}<span class="badge severity {{badgeClass .Severity}}">{{.Severity}}</span>{{else}}&ndash;{{end}}</td><td>{{.Date}}</td><td>{{.AgeDays}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>None</p>
+{{- end}}
+</section>
+{{- end}}
+`))
@@ -0,0 +1,69 @@
+package tdr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewPortfolio checks the counts, the open record lists and the effort total
+func TestNewPortfolio(t *testing.T) {
+	records := []Record{
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", Date: "2024-02-01", State: "Identified", Severity: "High", EffortEstimate: 3}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0002", Title: "Missing Tests", Date: "2024-01-01", State: "Approved", ProposedSol: "Write them", EffortEstimate: 5.5}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0003", Title: "Slow Build", Date: "2023-01-01", State: "Closed", Severity: "High", EffortEstimate: 8}},
+		{TechnicalDebt: TechnicalDebt{ID: "TDR-0004", Title: "Copy | Paste", Date: "2024-02-20", State: "Identified", Severity: "Low"}},
+	}
+	p := NewPortfolio(records, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), 2)
+
+	if p.Records != 4 || p.Open != 3 {
+		t.Errorf("Records, Open = %d, %d, want 4, 3", p.Records, p.Open)
+	}
+	if p.ByState[0] != (Count{"Identified", 2}) || p.ByState[5] != (Count{"Closed", 1}) {
+		t.Errorf("ByState = %v", p.ByState)
+	}
+	if p.BySeverity[1] != (Count{"High", 2}) || p.BySeverity[4] != (Count{"None", 1}) {
+		t.Errorf("BySeverity = %v", p.BySeverity)
+	}
+	if len(p.OldestOpen) != 2 || p.OldestOpen[0].ID != "TDR-0002" || p.OldestOpen[0].AgeDays != 60 || p.OldestOpen[1].ID != "TDR-0001" {
+		t.Errorf("OldestOpen = %v, want TDR-0002 (60 days) and TDR-0001", p.OldestOpen)
+	}
+	if len(p.WithoutSolution) != 2 || p.WithoutSolution[0].ID != "TDR-0001" || p.WithoutSolution[1].ID != "TDR-0004" {
+		t.Errorf("WithoutSolution = %v, want TDR-0001 and TDR-0004", p.WithoutSolution)
+	}
+	if p.TotalEffort != 8.5 || p.Unestimated != 1 {
+		t.Errorf("TotalEffort, Unestimated = %v, %d, want 8.5, 1", p.TotalEffort, p.Unestimated)
+	}
+
+	var markdown strings.Builder
+	if err := WriteReport(&markdown, "markdown", p); err != nil {
+		t.Fatalf("WriteReport(markdown) error = %v", err)
+	}
+	for _, want := range []string{"| Identified | 2 |", "| TDR-0004 | Copy \\| Paste | Identified | Low | 2024-02-20 | 10 |", "estimated effort of 8.5"} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Markdown report does not contain %q:\n%s", want, markdown.String())
+		}
+	}
+
+	var html strings.Builder
+	if err := WriteReport(&html, "html", p); err != nil {
+		t.Fatalf("WriteReport(html) error = %v", err)
+	}
+	if !strings.Contains(html.String(), "<td>Copy | Paste</td>") || !strings.Contains(html.String(), "<h2>Oldest Open Records</h2>") {
+		t.Errorf("HTML report is missing records:\n%s", html.String())
+	}
+
+	var data strings.Builder
+	if err := WriteReport(&data, "json", p); err != nil {
+		t.Fatalf("WriteReport(json) error = %v", err)
+	}
+	var decoded Portfolio
+	if err := json.Unmarshal([]byte(data.String()), &decoded); err != nil || decoded.TotalEffort != 8.5 {
+		t.Errorf("JSON report = %s, %v", data.String(), err)
+	}
+
+	if err := WriteReport(&data, "pdf", p); err == nil {
+		t.Error("WriteReport(pdf) succeeded, want error")
+	}
+}
@@ -0,0 +1,425 @@
+package tdr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigDir is the project directory that marks a TDR repository and holds its configuration
+const ConfigDir = ".tdr"
+
+// DefaultDirectory is where records are stored unless another directory is given on init
+const DefaultDirectory = "docs/tdr"
+
+// configFile is the name of the repository configuration inside ConfigDir
+const configFile = "config.yaml"
+
+// ErrNoRepository is returned when no TDR repository is found
+var ErrNoRepository = errors.New("no TDR repository found, run 'init' first")
+
+// Repository is a directory of sequentially numbered technical debt records,
+// managed in the style of adr-tools
+type Repository struct {
+	// Root is the project directory containing ConfigDir
+	Root string
+	// Dir is the directory holding the record files
+	Dir string
+	// Schema declares the custom fields of the records; nil if there are none
+	Schema *Schema
+}
+
+// repositoryConfig is the content of the repository configuration file
+type repositoryConfig struct {
+	Directory string `yaml:"directory"`
+}
+
+// recordFilePattern matches record filenames such as "0007-outdated-library.md"
+var recordFilePattern = regexp.MustCompile(`^(\d+)-.*\.md$`)
+
+// InitRepository creates a repository in root that stores records in dir,
+// which is relative to root. An empty dir selects DefaultDirectory.
+func InitRepository(root, dir string) (*Repository, error) {
+	if dir == "" {
+		dir = DefaultDirectory
+	}
+	if filepath.IsAbs(dir) {
+		return nil, fmt.Errorf("record directory %q must be relative to the project root", dir)
+	}
+
+	configPath := filepath.Join(root, ConfigDir, configFile)
+	if _, err := os.Stat(configPath); err == nil {
+		return nil, fmt.Errorf("a TDR repository is already initialized in %s", root)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ConfigDir), 0755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %w", ConfigDir, err)
+	}
+	data, err := yaml.Marshal(repositoryConfig{Directory: filepath.ToSlash(dir)})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("error writing repository configuration: %w", err)
+	}
+
+	repo := &Repository{Root: root, Dir: filepath.Join(root, dir)}
+	if err := os.MkdirAll(repo.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating record directory: %w", err)
+	}
+	return repo, nil
+}
+
+// OpenRepository finds the repository containing start by searching start and its parents
+func OpenRepository(start string) (*Repository, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, ConfigDir, configFile))
+		if err == nil {
+			var config repositoryConfig
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return nil, fmt.Errorf("invalid repository configuration: %w", err)
+			}
+			if config.Directory == "" {
+				config.Directory = DefaultDirectory
+			}
+			schema, err := loadRepositorySchema(dir)
+			if err != nil {
+				return nil, err
+			}
+			return &Repository{Root: dir, Dir: filepath.Join(dir, filepath.FromSlash(config.Directory)), Schema: schema}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNoRepository
+		}
+		dir = parent
+	}
+}
+
+// FormatID returns the record ID for a sequence number, e.g. 7 -> "TDR-0007"
+func FormatID(number int) string {
+	return fmt.Sprintf("TDR-%04d", number)
+}
+
+// ParseID returns the sequence number of a record ID. It accepts "TDR-0007",
+// "tdr-7" and plain numbers such as "7".
+func ParseID(id string) (int, error) {
+	digits := strings.TrimSpace(id)
+	if len(digits) > 4 && strings.EqualFold(digits[:4], "TDR-") {
+		digits = digits[4:]
+	}
+	number, err := strconv.Atoi(digits)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("invalid record ID %q, expected e.g. TDR-0007", id)
+	}
+	return number, nil
+}
+
+// Slug turns a title into a filename-friendly form, e.g. "Outdated Library!" -> "outdated-library"
+func Slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	slug := b.String()
+	if runes := []rune(slug); len(runes) > 60 {
+		slug = strings.TrimRight(string(runes[:60]), "-")
+	}
+	if slug == "" {
+		slug = "record"
+	}
+	return slug
+}
+
+// NextID returns the ID the next record created in the repository will get
+func (r *Repository) NextID() (string, error) {
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		return "", fmt.Errorf("error reading record directory: %w", err)
+	}
+	highest := 0
+	for _, entry := range entries {
+		if number := fileNumber(entry.Name()); number > highest {
+			highest = number
+		}
+	}
+	return FormatID(highest + 1), nil
+}
+
+// Create assigns the next ID to td, validates it, its custom fields and its relations and writes
+// it as a Markdown file named after its number and title. It returns the record with its ID and
+// the path of the new file.
+func (r *Repository) Create(td TechnicalDebt) (TechnicalDebt, string, error) {
+	id, err := r.NextID()
+	if err != nil {
+		return td, "", err
+	}
+	td.ID = id
+	td.Empty = false
+	if err := r.Validate(&td); err != nil {
+		return td, "", err
+	}
+
+	if err := r.ResolveRelations(&td); err != nil {
+		return td, "", err
+	}
+	links, err := r.Links()
+	if err != nil {
+		return td, "", err
+	}
+
+	number, _ := ParseID(id)
+	path := filepath.Join(r.Dir, fmt.Sprintf("%04d-%s.md", number, Slug(td.Title)))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return td, "", fmt.Errorf("error creating record file: %w", err)
+	}
+	if _, err := file.WriteString(generateMarkdown(td, links)); err != nil {
+		file.Close()
+		return td, "", fmt.Errorf("error writing record file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return td, "", err
+	}
+	return td, path, r.syncInverses(td, nil)
+}
+
+// Validate normalizes the custom fields of td to the schema of the repository
+// and checks the record with Validate and the schema
+func (r *Repository) Validate(td *TechnicalDebt) error {
+	r.Schema.Normalize(td)
+	if err := Validate(*td); err != nil {
+		return err
+	}
+	return r.Schema.Validate(*td)
+}
+
+// Record is a technical debt record stored in a repository file
+type Record struct {
+	TechnicalDebt
+	// Path is the file the record is stored in
+	Path string
+}
+
+// RecordFiles returns the paths of all record files in the repository, ordered by number
+func (r *Repository) RecordFiles() ([]string, error) {
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading record directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && recordFilePattern.MatchString(entry.Name()) {
+			paths = append(paths, filepath.Join(r.Dir, entry.Name()))
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return fileNumber(paths[i]) < fileNumber(paths[j])
+	})
+	return paths, nil
+}
+
+// Records loads every record in the repository, ordered by number
+func (r *Repository) Records() ([]Record, error) {
+	paths, err := r.RecordFiles()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		rec, err := LoadRecord(path)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Find loads the record with the given ID, see ParseID for the accepted forms
+func (r *Repository) Find(id string) (Record, error) {
+	number, err := ParseID(id)
+	if err != nil {
+		return Record{}, err
+	}
+	paths, err := r.RecordFiles()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, path := range paths {
+		if fileNumber(path) == number {
+			return LoadRecord(path)
+		}
+	}
+	return Record{}, fmt.Errorf("record %s not found", FormatID(number))
+}
+
+// Save validates the record and its custom fields and writes it back to its file. Relations are
+// linked to their record files but not checked, see ResolveRelations. Typed
+// relations added or removed since the file was last written are mirrored on
+// their target records.
+func (r *Repository) Save(rec Record) error {
+	if err := r.Validate(&rec.TechnicalDebt); err != nil {
+		return fmt.Errorf("%s: %w", rec.ID, err)
+	}
+	var previous []Relation
+	if old, err := LoadRecord(rec.Path); err == nil {
+		previous = old.Relations
+	}
+	links, err := r.Links()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(rec.Path, []byte(generateMarkdown(rec.TechnicalDebt, links)), 0644); err != nil {
+		return fmt.Errorf("error writing record file: %w", err)
+	}
+	return r.syncInverses(rec.TechnicalDebt, previous)
+}
+
+// Links returns a resolver from record IDs to the record files of the repository
+func (r *Repository) Links() (LinkResolver, error) {
+	paths, err := r.RecordFiles()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(paths))
+	for _, path := range paths {
+		base := filepath.Base(path)
+		names[fileNumber(path)] = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return func(id string) (string, bool) {
+		number, err := ParseID(id)
+		if err != nil {
+			return "", false
+		}
+		name, ok := names[number]
+		return name, ok
+	}, nil
+}
+
+// ResolveRelations checks that every relation of td refers to another record
+// in the repository and rewrites its target in the canonical form, e.g.
+// "7" -> "TDR-0007". Relations to ADRs are kept as they are.
+func (r *Repository) ResolveRelations(td *TechnicalDebt) error {
+	links, err := r.Links()
+	if err != nil {
+		return err
+	}
+	for i, rel := range td.Relations {
+		if !rel.Kind.RefersToRecord() {
+			continue
+		}
+		number, err := ParseID(rel.Target)
+		if err != nil {
+			return fmt.Errorf("relation %q is not a record ID", rel.Target)
+		}
+		id := FormatID(number)
+		if id == td.ID {
+			return fmt.Errorf("relation %s refers to the record itself", id)
+		}
+		if _, ok := links(id); !ok {
+			return fmt.Errorf("relation %s refers to a record that does not exist", id)
+		}
+		td.Relations[i].Target = id
+	}
+	return nil
+}
+
+// syncInverses keeps typed relations bidirectional: for every relation of td
+// that is not in previous, the inverse relation is added to the target record,
+// and for every relation that was removed, the inverse is removed again.
+func (r *Repository) syncInverses(td TechnicalDebt, previous []Relation) error {
+	changes := make(map[string][]Relation) // target ID -> relations to toggle
+	for _, rel := range td.Relations {
+		if _, ok := rel.Kind.Inverse(); ok && !slices.Contains(previous, rel) {
+			changes[rel.Target] = append(changes[rel.Target], rel)
+		}
+	}
+	for _, rel := range previous {
+		if _, ok := rel.Kind.Inverse(); ok && !slices.Contains(td.Relations, rel) {
+			changes[rel.Target] = append(changes[rel.Target], rel)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	links, err := r.Links()
+	if err != nil {
+		return err
+	}
+	for _, target := range slices.Sorted(maps.Keys(changes)) {
+		other, err := r.Find(target)
+		if err != nil {
+			// Dangling relations are reported by Lint, not fixed here
+			continue
+		}
+		changed := false
+		for _, rel := range changes[target] {
+			kind, _ := rel.Kind.Inverse()
+			inverse := Relation{Kind: kind, Target: td.ID}
+			has := slices.Contains(other.Relations, inverse)
+			switch added := slices.Contains(td.Relations, rel); {
+			case added && !has:
+				other.Relations = append(other.Relations, inverse)
+				changed = true
+			case !added && has:
+				other.Relations = slices.DeleteFunc(other.Relations, func(existing Relation) bool { return existing == inverse })
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := os.WriteFile(other.Path, []byte(generateMarkdown(other.TechnicalDebt, links)), 0644); err != nil {
+			return fmt.Errorf("error writing record file: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadRecord reads a record file written by a repository
+func LoadRecord(path string) (Record, error) {
+	td, err := ReadFile(path)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{TechnicalDebt: td, Path: path}, nil
+}
+
+// fileNumber returns the sequence number in a record filename, or 0 if there is none
+func fileNumber(path string) int {
+	match := recordFilePattern.FindStringSubmatch(filepath.Base(path))
+	if match == nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(match[1])
+	return number
+}
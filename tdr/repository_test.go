@@ -0,0 +1,243 @@
+package tdr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRepositoryCreate checks sequential numbering, filenames and the stored ID
+func TestRepositoryCreate(t *testing.T) {
+	root := t.TempDir()
+	if _, err := InitRepository(root, ""); err != nil {
+		t.Fatalf("InitRepository() error = %v", err)
+	}
+	if _, err := InitRepository(root, ""); err == nil {
+		t.Errorf("InitRepository() expected an error for an existing repository")
+	}
+
+	// The repository is found from a subdirectory of the project
+	sub := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := OpenRepository(sub)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	td := TechnicalDebt{
+		Author:  "Jane Doe",
+		Version: "1.0.0",
+		Date:    "2024-04-15",
+		State:   "Identified",
+	}
+	titles := []string{"Outdated Library", "Missing Tests: Payment Service!"}
+	wantFiles := []string{"0001-outdated-library.md", "0002-missing-tests-payment-service.md"}
+	for i, title := range titles {
+		td.Title = title
+		created, path, err := repo.Create(td)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if want := FormatID(i + 1); created.ID != want {
+			t.Errorf("Create() ID = %q, want %q", created.ID, want)
+		}
+		if filepath.Base(path) != wantFiles[i] {
+			t.Errorf("Create() path = %q, want file %q", path, wantFiles[i])
+		}
+		if filepath.Dir(path) != filepath.Join(repo.Root, DefaultDirectory) {
+			t.Errorf("Create() wrote to %q, want %q", filepath.Dir(path), DefaultDirectory)
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseMarkdown(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("ParseMarkdown() error = %v", err)
+		}
+		if parsed.ID != created.ID {
+			t.Errorf("stored ID = %q, want %q", parsed.ID, created.ID)
+		}
+	}
+
+	// Invalid records are not written and do not consume a number
+	if _, _, err := repo.Create(TechnicalDebt{Title: "Incomplete"}); err == nil {
+		t.Errorf("Create() expected a validation error")
+	}
+	if id, _ := repo.NextID(); id != "TDR-0003" {
+		t.Errorf("NextID() = %q, want TDR-0003", id)
+	}
+}
+
+// TestOpenRepositoryMissing checks the error outside of a repository
+func TestOpenRepositoryMissing(t *testing.T) {
+	if _, err := OpenRepository(t.TempDir()); err != ErrNoRepository {
+		t.Errorf("OpenRepository() error = %v, want ErrNoRepository", err)
+	}
+}
+
+// TestParseID checks the accepted spellings of record IDs
+func TestParseID(t *testing.T) {
+	for _, id := range []string{"TDR-0007", "tdr-7", "7", " 0007 "} {
+		if n, err := ParseID(id); err != nil || n != 7 {
+			t.Errorf("ParseID(%q) = %d, %v, want 7", id, n, err)
+		}
+	}
+	for _, id := range []string{"", "TDR-", "ADR-7", "TDR-0"} {
+		if _, err := ParseID(id); err == nil {
+			t.Errorf("ParseID(%q) expected an error", id)
+		}
+	}
+}
+
+// TestSlug checks the filename form of titles
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Outdated Library":                "outdated-library",
+		"  Missing Tests: Payment!  ":     "missing-tests-payment",
+		"Größere Änderungen":              "größere-änderungen",
+		"!!!":                             "record",
+		strings.Repeat("long title ", 10): strings.TrimRight(strings.Repeat("long-title-", 6)[:60], "-"),
+	}
+	for title, want := range tests {
+		if got := Slug(title); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+// TestRepositoryRelations checks that relations are resolved, linked and linted
+func TestRepositoryRelations(t *testing.T) {
+	repo := newTestRepository(t, "Outdated Library")
+
+	td := TechnicalDebt{
+		Title:     "Missing Tests",
+		Author:    "Jane Doe",
+		Version:   "1.0.0",
+		Date:      "2024-04-15",
+		State:     "Identified",
+		Relations: []Relation{{Target: "1"}},
+	}
+	created, path, err := repo.Create(td)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(created.Relations) != 1 || created.Relations[0].Target != "TDR-0001" {
+		t.Errorf("Create() relations = %q, want [TDR-0001]", created.Relations)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "- [TDR-0001](0001-outdated-library.md)") {
+		t.Errorf("record does not link its relation to the target file:\n%s", data)
+	}
+
+	for _, rel := range []string{"TDR-0042", "ADR-1", "TDR-0003"} {
+		td.Relations = []Relation{{Target: rel}}
+		if _, _, err := repo.Create(td); err == nil {
+			t.Errorf("Create() accepted relation %q", rel)
+		}
+	}
+
+	// Removing the target leaves a dangling reference
+	if err := os.Remove(filepath.Join(repo.Dir, "0001-outdated-library.md")); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := repo.Lint()
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, "TDR-0001 refers to a record that does not exist") {
+		t.Errorf("Lint() = %v, want one dangling reference", problems)
+	}
+}
+
+// TestRepositoryInverseRelations checks that typed relations are mirrored on their targets
+func TestRepositoryInverseRelations(t *testing.T) {
+	repo := newTestRepository(t, "Outdated Library", "Missing Tests")
+
+	rec, err := repo.Find("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Relations = []Relation{{Kind: Blocks, Target: "TDR-0001"}, {Kind: CausedBy, Target: "ADR-0012"}}
+	if err := repo.Save(rec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	target, err := repo.Find("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Relation{{Kind: BlockedBy, Target: "TDR-0002"}}
+	if !reflect.DeepEqual(target.Relations, want) {
+		t.Errorf("target relations = %v, want %v", target.Relations, want)
+	}
+	if problems, err := repo.Lint(); err != nil || len(problems) != 0 {
+		t.Errorf("Lint() = %v, %v, want no problems", problems, err)
+	}
+
+	// Removing the relation removes its inverse
+	rec.Relations = nil
+	if err := repo.Save(rec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if target, _ = repo.Find("1"); len(target.Relations) != 0 {
+		t.Errorf("target relations = %v after removal, want none", target.Relations)
+	}
+
+	// A one-sided relation is reported
+	target.Relations = []Relation{{Kind: DependsOn, Target: "TDR-0002"}}
+	if err := os.WriteFile(target.Path, []byte(GenerateMarkdown(target.TechnicalDebt)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := repo.Lint()
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, `missing relation "required-by TDR-0001"`) {
+		t.Errorf("Lint() = %v, want one missing inverse relation", problems)
+	}
+}
+
+// TestRepositorySchema checks that records are created according to the schema of the repository
+func TestRepositorySchema(t *testing.T) {
+	root := t.TempDir()
+	if _, err := InitRepository(root, ""); err != nil {
+		t.Fatalf("InitRepository() error = %v", err)
+	}
+	schema := "fields:\n  - name: Component\n    required: true\n    values: [Backend, Frontend]\n  - name: Jira Key\n"
+	if err := os.WriteFile(filepath.Join(root, ConfigDir, "schema.yaml"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := OpenRepository(root)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+	if repo.Schema == nil || len(repo.Schema.Fields) != 2 {
+		t.Fatalf("Schema = %+v, want two fields", repo.Schema)
+	}
+
+	td := TechnicalDebt{Title: "Outdated Library", Author: "Jane Doe", Version: "1.0.0", Date: "2024-04-15", State: "Identified"}
+	if _, _, err := repo.Create(td); err == nil || !strings.Contains(err.Error(), "Component is required") {
+		t.Errorf("Create() error = %v, want the required field reported", err)
+	}
+	td.CustomFields = Fields{{"component", "frontend"}}
+	_, path, err := repo.Create(td)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	rec, err := LoadRecord(path)
+	if err != nil {
+		t.Fatalf("LoadRecord() error = %v", err)
+	}
+	if want := (Fields{{"Component", "Frontend"}, {"Jira Key", ""}}); !reflect.DeepEqual(rec.CustomFields, want) {
+		t.Errorf("stored CustomFields = %+v, want %+v", rec.CustomFields, want)
+	}
+}
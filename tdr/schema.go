@@ -0,0 +1,393 @@
+package tdr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaFile is the name of the schema declaring the custom fields of a
+// repository, inside ConfigDir
+const schemaFile = "schema.yaml"
+
+// Types of custom fields
+const (
+	FieldString = "string"
+	FieldNumber = "number"
+	FieldDate   = "date"
+)
+
+// fieldTypes are the types a custom field may be declared with
+var fieldTypes = []string{FieldString, FieldNumber, FieldDate}
+
+// Schema declares the custom fields an organization tracks in addition to the
+// built-in fields of a record, such as the component or the owning team
+type Schema struct {
+	Fields []FieldSpec `yaml:"fields"`
+}
+
+// FieldSpec declares a custom field
+type FieldSpec struct {
+	// Name is the label of the field, used as Markdown heading, Excel and CSV
+	// column and JSON and YAML key, e.g. "Jira Key"
+	Name string `yaml:"name"`
+	// Type is one of FieldString (the default), FieldNumber and FieldDate
+	Type string `yaml:"type,omitempty"`
+	// Required fields must not be empty
+	Required bool `yaml:"required,omitempty"`
+	// Values are the allowed values; any value of the type is allowed if empty
+	Values []string `yaml:"values,omitempty"`
+	// Prompt is the text shown when asking for the field, and its description
+	Prompt string `yaml:"prompt,omitempty"`
+}
+
+// Field is the value of a custom field of a record
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Fields are the custom fields of a record in the order of the schema. They
+// are written to JSON and YAML as a mapping from name to value.
+type Fields []Field
+
+// Get returns the value of the named field, or "" if the record has no such field
+func (f Fields) Get(name string) string {
+	if i := f.index(name); i >= 0 {
+		return f[i].Value
+	}
+	return ""
+}
+
+// Has reports whether the record has the named field, even if it is empty
+func (f Fields) Has(name string) bool {
+	return f.index(name) >= 0
+}
+
+// Set replaces the value of the named field, or adds the field at the end
+func (f *Fields) Set(name, value string) {
+	if i := f.index(name); i >= 0 {
+		(*f)[i].Value = value
+		return
+	}
+	*f = append(*f, Field{Name: name, Value: value})
+}
+
+// index returns the position of the named field, or -1
+func (f Fields) index(name string) int {
+	return slices.IndexFunc(f, func(field Field) bool { return field.Name == name })
+}
+
+// MarshalJSON writes the fields as an object, keeping their order
+func (f Fields) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON reads an object of fields in the order of its keys. Numbers
+// and booleans are accepted as values and kept as text.
+func (f *Fields) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return errors.New("custom_fields must be an object")
+	}
+	*f = nil
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := token.(string)
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case nil:
+			f.Set(name, "")
+		case string, json.Number, bool:
+			f.Set(name, fmt.Sprint(v))
+		default:
+			return fmt.Errorf("custom field %q must be a text, number or date", name)
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// MarshalYAML writes the fields as a mapping, keeping their order
+func (f Fields) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range f {
+		var value yaml.Node
+		if err := value.Encode(field.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Name}, &value)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML reads a mapping of fields in the order of its keys
+func (f *Fields) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: custom_fields must be a mapping", node.Line)
+	}
+	*f = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, value := node.Content[i], node.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: custom field %q must be a text, number or date", value.Line, name.Value)
+		}
+		if value.Tag == "!!null" {
+			f.Set(name.Value, "")
+			continue
+		}
+		f.Set(name.Value, value.Value)
+	}
+	return nil
+}
+
+// LoadSchema reads and checks a schema file
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Schema
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: invalid schema: %w", path, err)
+	}
+	if err := s.check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// loadRepositorySchema reads the schema of the repository in root, or returns
+// nil if the repository has none
+func loadRepositorySchema(root string) (*Schema, error) {
+	s, err := LoadSchema(filepath.Join(root, ConfigDir, schemaFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// reservedNames are the headings and columns of the built-in fields, which
+// custom fields must not reuse
+var reservedNames = append(slices.Clone(excelHeaders), "Impact", "History")
+
+// check rejects fields without a valid name or type and allowed values that
+// are not of the type of their field
+func (s *Schema) check() error {
+	seen := make(map[string]bool)
+	for i := range s.Fields {
+		spec := &s.Fields[i]
+		spec.Name = strings.TrimSpace(spec.Name)
+		switch {
+		case spec.Name == "":
+			return fmt.Errorf("field %d has no name", i+1)
+		case strings.ContainsAny(spec.Name, "\n#"):
+			return fmt.Errorf("field name %q must not contain line breaks or '#'", spec.Name)
+		case slices.ContainsFunc(reservedNames, func(name string) bool { return strings.EqualFold(name, spec.Name) }):
+			return fmt.Errorf("field name %q is used by a built-in field", spec.Name)
+		case seen[strings.ToLower(spec.Name)]:
+			return fmt.Errorf("duplicate field %q", spec.Name)
+		}
+		seen[strings.ToLower(spec.Name)] = true
+
+		if spec.Type == "" {
+			spec.Type = FieldString
+		}
+		if !slices.Contains(fieldTypes, spec.Type) {
+			return fmt.Errorf("field %q has the invalid type %q, allowed types are: %s",
+				spec.Name, spec.Type, strings.Join(fieldTypes, ", "))
+		}
+		for _, value := range spec.Values {
+			if err := spec.checkType(value); err != nil {
+				return fmt.Errorf("field %q: allowed value %q is not a %s", spec.Name, value, spec.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Field returns the declaration of the named field, ignoring case, or nil if
+// it is not declared. A nil schema declares no fields.
+func (s *Schema) Field(name string) *FieldSpec {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Fields {
+		if strings.EqualFold(s.Fields[i].Name, name) {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Normalize orders the custom fields of td like the schema, adding the
+// declared fields td lacks as empty, and rewrites field names and allowed
+// values given in another case in their declared form. Undeclared fields are
+// kept at the end for Validate to report.
+func (s *Schema) Normalize(td *TechnicalDebt) {
+	if s == nil {
+		return
+	}
+	var fields Fields
+	for _, spec := range s.Fields {
+		value := ""
+		for _, field := range td.CustomFields {
+			if strings.EqualFold(field.Name, spec.Name) {
+				value = spec.normalize(field.Value)
+			}
+		}
+		fields = append(fields, Field{Name: spec.Name, Value: value})
+	}
+	for _, field := range td.CustomFields {
+		if s.Field(field.Name) == nil {
+			fields = append(fields, field)
+		}
+	}
+	td.CustomFields = fields
+}
+
+// Validate ensures that td only has declared custom fields, that required
+// fields are present and that values match their type and allowed values.
+// A nil schema allows no custom fields.
+func (s *Schema) Validate(td TechnicalDebt) error {
+	for _, field := range td.CustomFields {
+		spec := s.Field(field.Name)
+		if spec == nil {
+			return fmt.Errorf("unknown field %q, declare custom fields in %s", field.Name,
+				filepath.ToSlash(filepath.Join(ConfigDir, schemaFile)))
+		}
+		if err := spec.Check(field.Value); err != nil {
+			return err
+		}
+	}
+	if s == nil {
+		return nil
+	}
+	for _, spec := range s.Fields {
+		if spec.Required && strings.TrimSpace(td.CustomFields.Get(spec.Name)) == "" {
+			return fmt.Errorf("%s is required", spec.Name)
+		}
+	}
+	return nil
+}
+
+// Placeholders returns the declared fields with the hints of empty templates
+// as values, e.g. "[Enter Component Here: Backend / Frontend]"
+func (s *Schema) Placeholders() Fields {
+	if s == nil {
+		return nil
+	}
+	var fields Fields
+	for _, spec := range s.Fields {
+		fields = append(fields, Field{Name: spec.Name, Value: spec.Placeholder()})
+	}
+	return fields
+}
+
+// Placeholder returns the hint shown for the field in empty templates
+func (spec FieldSpec) Placeholder() string {
+	switch {
+	case len(spec.Values) > 0:
+		return fmt.Sprintf("[Enter %s Here: %s]", spec.Name, strings.Join(spec.Values, " / "))
+	case spec.Type == FieldDate:
+		return fmt.Sprintf("[Enter %s Here: YYYY-MM-DD]", spec.Name)
+	}
+	return fmt.Sprintf("[Enter %s Here]", spec.Name)
+}
+
+// Description returns the prompt of the field, or a description derived from
+// its type and allowed values
+func (spec FieldSpec) Description() string {
+	switch {
+	case spec.Prompt != "":
+		return spec.Prompt
+	case len(spec.Values) > 0:
+		return fmt.Sprintf("The %s: %s.", spec.Name, strings.Join(spec.Values, ", "))
+	case spec.Type == FieldDate:
+		return fmt.Sprintf("The %s as YYYY-MM-DD.", spec.Name)
+	case spec.Type == FieldNumber:
+		return fmt.Sprintf("The %s as a number.", spec.Name)
+	}
+	return ""
+}
+
+// Check returns an error if a non-empty value is not of the type of the field
+// or not one of its allowed values
+func (spec FieldSpec) Check(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(spec.Values) > 0 {
+		if !slices.Contains(spec.Values, value) {
+			return fmt.Errorf("%s %q is invalid, allowed values are: %s", spec.Name, value, strings.Join(spec.Values, ", "))
+		}
+		return nil
+	}
+	if err := spec.checkType(value); err != nil {
+		return fmt.Errorf("%s %q is invalid, %w", spec.Name, value, err)
+	}
+	return nil
+}
+
+// checkType returns an error if value is not of the type of the field
+func (spec FieldSpec) checkType(value string) error {
+	switch spec.Type {
+	case FieldNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("use a number")
+		}
+	case FieldDate:
+		if _, err := time.Parse(DateFormat, value); err != nil {
+			return errors.New("use YYYY-MM-DD")
+		}
+	}
+	return nil
+}
+
+// normalize returns the allowed value matching value in any case, or value
+func (spec FieldSpec) normalize(value string) string {
+	for _, allowed := range spec.Values {
+		if strings.EqualFold(strings.TrimSpace(value), allowed) {
+			return allowed
+		}
+	}
+	return value
+}
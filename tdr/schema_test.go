@@ -0,0 +1,168 @@
+package tdr
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testSchema declares the custom fields used by the tests
+var testSchema = &Schema{Fields: []FieldSpec{
+	{Name: "Component", Type: FieldString, Required: true, Values: []string{"Backend", "Frontend"}},
+	{Name: "Jira Key", Type: FieldString},
+	{Name: "Due Date", Type: FieldDate},
+	{Name: "Budget", Type: FieldNumber},
+}}
+
+// TestLoadSchema checks that a schema file is read and its types defaulted
+func TestLoadSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	data := "fields:\n  - name: Component\n    required: true\n    values: [Backend, Frontend]\n    prompt: Which component?\n  - name: Due Date\n    type: date\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSchema(path)
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+	want := []FieldSpec{
+		{Name: "Component", Type: FieldString, Required: true, Values: []string{"Backend", "Frontend"}, Prompt: "Which component?"},
+		{Name: "Due Date", Type: FieldDate},
+	}
+	if !reflect.DeepEqual(s.Fields, want) {
+		t.Errorf("Fields = %+v, want %+v", s.Fields, want)
+	}
+}
+
+// TestLoadSchemaInvalid checks that malformed field declarations are rejected
+func TestLoadSchemaInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "Unknown key", data: "fields:\n  - name: Team\n    label: Owning Team\n", want: "label"},
+		{name: "No name", data: "fields:\n  - type: date\n", want: "has no name"},
+		{name: "Built-in name", data: "fields:\n  - name: severity\n", want: "built-in field"},
+		{name: "Duplicate", data: "fields:\n  - name: Team\n  - name: team\n", want: "duplicate field"},
+		{name: "Unknown type", data: "fields:\n  - name: Team\n    type: person\n", want: "invalid type"},
+		{name: "Invalid value", data: "fields:\n  - name: Budget\n    type: number\n    values: [low]\n", want: "not a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schema.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadSchema(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadSchema() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestSchemaNormalize checks the order, case and completion of custom fields
+func TestSchemaNormalize(t *testing.T) {
+	td := TechnicalDebt{CustomFields: Fields{{"Owner", "Jane Doe"}, {"due date", "2024-06-30"}, {"component", "backend"}}}
+	testSchema.Normalize(&td)
+	want := Fields{{"Component", "Backend"}, {"Jira Key", ""}, {"Due Date", "2024-06-30"}, {"Budget", ""}, {"Owner", "Jane Doe"}}
+	if !reflect.DeepEqual(td.CustomFields, want) {
+		t.Errorf("Normalize() = %+v, want %+v", td.CustomFields, want)
+	}
+}
+
+// TestSchemaValidate checks required fields, types, allowed values and undeclared fields
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *Schema
+		fields Fields
+		want   string
+	}{
+		{name: "Valid", schema: testSchema, fields: Fields{{"Component", "Backend"}, {"Due Date", "2024-06-30"}, {"Budget", "1.5"}}},
+		{name: "Required", schema: testSchema, fields: Fields{{"Component", ""}}, want: "Component is required"},
+		{name: "Allowed values", schema: testSchema, fields: Fields{{"Component", "Mobile"}}, want: "allowed values are: Backend, Frontend"},
+		{name: "Date", schema: testSchema, fields: Fields{{"Component", "Backend"}, {"Due Date", "30.06.2024"}}, want: "use YYYY-MM-DD"},
+		{name: "Number", schema: testSchema, fields: Fields{{"Component", "Backend"}, {"Budget", "a lot"}}, want: "use a number"},
+		{name: "Undeclared", schema: testSchema, fields: Fields{{"Owner", "Jane Doe"}}, want: `unknown field "Owner"`},
+		{name: "No schema", schema: nil, fields: Fields{{"Component", "Backend"}}, want: `unknown field "Component"`},
+		{name: "No schema and no fields", schema: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate(TechnicalDebt{CustomFields: tt.fields})
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate() error = %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCustomFieldsRendered checks that every renderer writes the custom fields
+func TestCustomFieldsRendered(t *testing.T) {
+	td := TechnicalDebt{
+		Title:        "Outdated Library",
+		Author:       "Jane Doe",
+		Version:      "1.0.0",
+		Date:         "2024-04-15",
+		State:        "Identified",
+		CustomFields: Fields{{"Component", "Backend"}, {"Jira Key", "PAY-1234"}},
+	}
+	for _, r := range renderers {
+		t.Run(r.Name(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.Render(&buf, td); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			switch r.(type) {
+			case PDFRenderer:
+				// The text of the compressed PDF streams cannot be searched
+				if buf.Len() == 0 {
+					t.Errorf("Render() wrote nothing")
+				}
+			case ExcelRenderer:
+				rows, err := ReadWorkbook(&buf, nil)
+				if err != nil {
+					t.Fatalf("ReadWorkbook() error = %v", err)
+				}
+				var got TechnicalDebt
+				if err := rows[0].Apply(&got); err != nil || !reflect.DeepEqual(got.CustomFields, td.CustomFields) {
+					t.Errorf("CustomFields = %+v, %v, want %+v", got.CustomFields, err, td.CustomFields)
+				}
+			default:
+				for _, text := range []string{"Jira Key", "PAY-1234"} {
+					if !strings.Contains(buf.String(), text) {
+						t.Errorf("Render() output lacks %q", text)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCustomFieldsDecode checks that custom fields keep their order in JSON and YAML
+func TestCustomFieldsDecode(t *testing.T) {
+	want := Fields{{"Jira Key", "PAY-1234"}, {"Component", "Backend"}, {"Budget", "1200"}}
+	inputs := map[string]string{
+		"record.json": `{"title": "Outdated Library", "custom_fields": {"Jira Key": "PAY-1234", "Component": "Backend", "Budget": 1200}}`,
+		"record.yaml": "title: Outdated Library\ncustom_fields:\n  Jira Key: PAY-1234\n  Component: Backend\n  Budget: 1200\n",
+	}
+	for name, input := range inputs {
+		td, err := Decode(strings.NewReader(input), name)
+		if err != nil {
+			t.Fatalf("Decode(%s) error = %v", name, err)
+		}
+		if !reflect.DeepEqual(td.CustomFields, want) {
+			t.Errorf("Decode(%s) = %+v, want %+v", name, td.CustomFields, want)
+		}
+	}
+	if _, err := Decode(strings.NewReader(`{"custom_fields": {"Component": ["Backend"]}}`), "record.json"); err == nil {
+		t.Errorf("Decode() expected an error for a list value")
+	}
+}
@@ -0,0 +1,93 @@
+package tdr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transitions defines the states a record may move to from each state. Closed
+// and Rejected are final.
+var Transitions = map[string][]string{
+	"Identified":  {"Analyzed", "Rejected"},
+	"Analyzed":    {"Approved", "Rejected"},
+	"Approved":    {"In Progress", "Rejected"},
+	"In Progress": {"Resolved"},
+	"Resolved":    {"Closed"},
+	"Closed":      nil,
+	"Rejected":    nil,
+}
+
+// Settled reports whether a record in the given state needs no further work,
+// i.e. it is Resolved, Closed or Rejected
+func Settled(state string) bool {
+	return state == "Resolved" || state == "Closed" || state == "Rejected"
+}
+
+// Transition is an entry in the state history of a record
+type Transition struct {
+	Date  string `json:"date" yaml:"date"`
+	From  string `json:"from" yaml:"from"`
+	To    string `json:"to" yaml:"to"`
+	Actor string `json:"actor" yaml:"actor"`
+}
+
+// CanTransition reports whether a record may move from one state to another
+func CanTransition(from, to string) bool {
+	for _, next := range Transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeState moves the record to the given state, rejecting moves that are
+// not allowed by Transitions, and appends the change to its history
+func (td *TechnicalDebt) ChangeState(to, actor string, date time.Time) error {
+	state, err := ParseState(to)
+	if err != nil {
+		return err
+	}
+	if state == td.State {
+		return fmt.Errorf("record is already in state %q", state)
+	}
+	if !CanTransition(td.State, state) {
+		next := Transitions[td.State]
+		if len(next) == 0 {
+			return fmt.Errorf("cannot move from %q to %q: %q is a final state", td.State, state, td.State)
+		}
+		return fmt.Errorf("cannot move from %q to %q, allowed next states are: %s", td.State, state, strings.Join(next, ", "))
+	}
+	if strings.TrimSpace(actor) == "" {
+		return fmt.Errorf("the actor of the transition is required")
+	}
+
+	td.History = append(td.History, Transition{
+		Date:  date.Format(DateFormat),
+		From:  td.State,
+		To:    state,
+		Actor: strings.TrimSpace(actor),
+	})
+	td.State = state
+	return nil
+}
+
+// validateHistory checks that the history is a chain of allowed transitions ending in the current state
+func validateHistory(td TechnicalDebt) error {
+	for i, t := range td.History {
+		if _, err := time.Parse(DateFormat, t.Date); err != nil {
+			return fmt.Errorf("History entry %d has invalid date %q, use YYYY-MM-DD", i+1, t.Date)
+		}
+		if i > 0 && t.From != td.History[i-1].To {
+			return fmt.Errorf("History entry %d starts in %q but the previous entry ended in %q", i+1, t.From, td.History[i-1].To)
+		}
+		if !CanTransition(t.From, t.To) {
+			return fmt.Errorf("History entry %d moves from %q to %q, which is not allowed", i+1, t.From, t.To)
+		}
+	}
+	if n := len(td.History); n > 0 && td.History[n-1].To != td.State {
+		return fmt.Errorf("State %q does not match the last History entry %q", td.State, td.History[n-1].To)
+	}
+	return nil
+}
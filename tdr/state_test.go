@@ -0,0 +1,135 @@
+package tdr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestChangeState checks allowed and rejected transitions and the recorded history
+func TestChangeState(t *testing.T) {
+	td := TechnicalDebt{
+		Title:   "Outdated Library",
+		Author:  "Jane Doe",
+		Version: "1.0.0",
+		Date:    "2024-04-15",
+		State:   "Identified",
+	}
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := td.ChangeState("Closed", "Jane Doe", day); err == nil {
+		t.Errorf("ChangeState() allowed Identified -> Closed")
+	}
+	for _, state := range []string{"analyzed", "Approved", "In Progress", "Resolved", "Closed"} {
+		if err := td.ChangeState(state, "Jane Doe", day); err != nil {
+			t.Fatalf("ChangeState(%q) error = %v", state, err)
+		}
+	}
+	if err := td.ChangeState("Rejected", "Jane Doe", day); err == nil {
+		t.Errorf("ChangeState() allowed a transition out of the final state Closed")
+	}
+
+	if td.State != "Closed" || len(td.History) != 5 {
+		t.Fatalf("State = %q with %d history entries, want Closed with 5", td.State, len(td.History))
+	}
+	want := Transition{Date: "2024-05-01", From: "Identified", To: "Analyzed", Actor: "Jane Doe"}
+	if td.History[0] != want {
+		t.Errorf("History[0] = %+v, want %+v", td.History[0], want)
+	}
+	if err := Validate(td); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+// TestRejectedFromEarlyStates checks that only early states may be rejected
+func TestRejectedFromEarlyStates(t *testing.T) {
+	for _, state := range AllowedStates {
+		want := state == "Identified" || state == "Analyzed" || state == "Approved"
+		if got := CanTransition(state, "Rejected"); got != want {
+			t.Errorf("CanTransition(%q, Rejected) = %v, want %v", state, got, want)
+		}
+	}
+}
+
+// TestValidateHistory checks that inconsistent histories are rejected
+func TestValidateHistory(t *testing.T) {
+	base := TechnicalDebt{
+		Title:   "Outdated Library",
+		Author:  "Jane Doe",
+		Version: "1.0.0",
+		Date:    "2024-04-15",
+		State:   "Approved",
+	}
+	tests := []struct {
+		name    string
+		history []Transition
+		wantErr string
+	}{
+		{
+			name: "Valid chain",
+			history: []Transition{
+				{Date: "2024-04-16", From: "Identified", To: "Analyzed", Actor: "Jane Doe"},
+				{Date: "2024-04-17", From: "Analyzed", To: "Approved", Actor: "John Roe"},
+			},
+		},
+		{
+			name: "Skipped state",
+			history: []Transition{
+				{Date: "2024-04-16", From: "Identified", To: "Approved", Actor: "Jane Doe"},
+			},
+			wantErr: "not allowed",
+		},
+		{
+			name: "Broken chain",
+			history: []Transition{
+				{Date: "2024-04-16", From: "Identified", To: "Analyzed", Actor: "Jane Doe"},
+				{Date: "2024-04-17", From: "Approved", To: "In Progress", Actor: "Jane Doe"},
+			},
+			wantErr: "previous entry",
+		},
+		{
+			name: "State differs from history",
+			history: []Transition{
+				{Date: "2024-04-16", From: "Identified", To: "Analyzed", Actor: "Jane Doe"},
+			},
+			wantErr: "does not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := base
+			td.History = tt.history
+			err := Validate(td)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestHistoryMarkdownRoundTrip checks that the history table survives parsing, including escaped pipes
+func TestHistoryMarkdownRoundTrip(t *testing.T) {
+	td := TechnicalDebt{
+		ID:      "TDR-0007",
+		Title:   "Outdated Library",
+		Author:  "Jane Doe",
+		Version: "1.0.0",
+		Date:    "2024-04-15",
+		State:   "Analyzed",
+		History: []Transition{
+			{Date: "2024-04-16", From: "Identified", To: "Analyzed", Actor: "Jane | Doe"},
+		},
+	}
+	got, err := ParseMarkdown(strings.NewReader(GenerateMarkdown(td)))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, td) {
+		t.Errorf("ParseMarkdown() = %+v, want %+v", got, td)
+	}
+}
@@ -0,0 +1,119 @@
+// Package tdr provides the Technical Debt Record (TDR) model, its validation
+// and the renderers that turn a record into Markdown, ASCII, PDF or Excel.
+package tdr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TechnicalDebt represents a technical debt record
+type TechnicalDebt struct {
+	ID                string       `json:"id,omitempty" yaml:"id,omitempty"`
+	Title             string       `json:"title" yaml:"title"`
+	Author            string       `json:"author" yaml:"author"`
+	Version           string       `json:"version" yaml:"version"`
+	Date              string       `json:"date" yaml:"date"`
+	State             string       `json:"state" yaml:"state"`
+	Relations         []Relation   `json:"relations,omitempty" yaml:"relations,omitempty"`
+	Summary           string       `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Context           string       `json:"context,omitempty" yaml:"context,omitempty"`
+	ImpactTech        string       `json:"technical_impact,omitempty" yaml:"technical_impact,omitempty"`
+	ImpactBus         string       `json:"business_impact,omitempty" yaml:"business_impact,omitempty"`
+	Symptoms          string       `json:"symptoms,omitempty" yaml:"symptoms,omitempty"`
+	Severity          string       `json:"severity,omitempty" yaml:"severity,omitempty"`
+	PotentialRisks    string       `json:"potential_risks,omitempty" yaml:"potential_risks,omitempty"`
+	ProposedSol       string       `json:"proposed_solution,omitempty" yaml:"proposed_solution,omitempty"`
+	CostDelay         string       `json:"cost_of_delay,omitempty" yaml:"cost_of_delay,omitempty"`
+	Effort            string       `json:"effort,omitempty" yaml:"effort,omitempty"`
+	EffortEstimate    float64      `json:"effort_estimate,omitempty" yaml:"effort_estimate,omitempty"`
+	CostOfDelayWeekly float64      `json:"cost_of_delay_per_week,omitempty" yaml:"cost_of_delay_per_week,omitempty"`
+	RiskReduction     float64      `json:"risk_reduction,omitempty" yaml:"risk_reduction,omitempty"`
+	InterestRate      float64      `json:"interest_rate,omitempty" yaml:"interest_rate,omitempty"`
+	Dependencies      string       `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Additional        string       `json:"additional_notes,omitempty" yaml:"additional_notes,omitempty"`
+	CustomFields      Fields       `json:"custom_fields,omitempty" yaml:"custom_fields,omitempty"`
+	History           []Transition `json:"history,omitempty" yaml:"history,omitempty"`
+	Empty             bool         `json:"-" yaml:"-"`
+}
+
+// AllowedStates defines the possible states of a Technical Debt Record
+var AllowedStates = []string{
+	"Identified",
+	"Analyzed",
+	"Approved",
+	"In Progress",
+	"Resolved",
+	"Closed",
+	"Rejected",
+}
+
+// AllowedSeverities defines the possible severities of a Technical Debt Record, from highest to lowest
+var AllowedSeverities = []string{
+	"Critical",
+	"High",
+	"Medium",
+	"Low",
+}
+
+// DateFormat is the layout of the Date field
+const DateFormat = "2006-01-02"
+
+// ParseState returns the allowed state matching s, ignoring case
+func ParseState(s string) (string, error) {
+	for _, state := range AllowedStates {
+		if strings.EqualFold(strings.TrimSpace(s), state) {
+			return state, nil
+		}
+	}
+	return "", fmt.Errorf("invalid state %q, allowed states are: %s", s, strings.Join(AllowedStates, ", "))
+}
+
+// ParseSeverity returns the allowed severity matching s, ignoring case
+func ParseSeverity(s string) (string, error) {
+	for _, severity := range AllowedSeverities {
+		if strings.EqualFold(strings.TrimSpace(s), severity) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity %q, allowed severities are: %s", s, strings.Join(AllowedSeverities, ", "))
+}
+
+// Validate ensures all required fields are present and well-formed
+func Validate(td TechnicalDebt) error {
+	if td.Title == "" {
+		return errors.New("Title is required")
+	}
+	if strings.ContainsAny(td.Title, "\r\n") {
+		return errors.New("Title must not contain line breaks")
+	}
+	if td.Author == "" {
+		return errors.New("Author is required")
+	}
+	if td.Version == "" {
+		return errors.New("Version is required")
+	}
+	if td.Date == "" {
+		return errors.New("Date is required")
+	}
+	if td.State == "" {
+		return errors.New("State is required")
+	}
+	if _, err := time.Parse(DateFormat, td.Date); err != nil {
+		return fmt.Errorf("Date %q is invalid, use YYYY-MM-DD", td.Date)
+	}
+	if state, err := ParseState(td.State); err != nil || state != td.State {
+		return fmt.Errorf("State %q is invalid, allowed states are: %s", td.State, strings.Join(AllowedStates, ", "))
+	}
+	if td.Severity != "" {
+		if severity, err := ParseSeverity(td.Severity); err != nil || severity != td.Severity {
+			return fmt.Errorf("Severity %q is invalid, allowed severities are: %s", td.Severity, strings.Join(AllowedSeverities, ", "))
+		}
+	}
+	if err := validateEstimates(td); err != nil {
+		return err
+	}
+	return validateHistory(td)
+}
@@ -1,14 +1,17 @@
-// generate-td_test.go
-package main
+// tdr_test.go
+package tdr
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
 
-// TestValidateTechnicalDebt checks the validation of the TechnicalDebt structure
-func TestValidateTechnicalDebt(t *testing.T) {
+// TestValidate checks the validation of the TechnicalDebt structure
+func TestValidate(t *testing.T) {
 	tests := []struct {
 		name    string
 		td      TechnicalDebt
@@ -35,6 +38,17 @@ func TestValidateTechnicalDebt(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Multi-line Title",
+			td: TechnicalDebt{
+				Title:   "Legacy\n## State\n\nClosed",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "2024-04-15",
+				State:   "Analyzed",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Missing Author",
 			td: TechnicalDebt{
@@ -75,12 +89,58 @@ func TestValidateTechnicalDebt(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid Date",
+			td: TechnicalDebt{
+				Title:   "Outdated Library",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "15.04.2024",
+				State:   "Analyzed",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown State",
+			td: TechnicalDebt{
+				Title:   "Outdated Library",
+				Author:  "Jane Doe",
+				Version: "1.0.0",
+				Date:    "2024-04-15",
+				State:   "Done",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown Severity",
+			td: TechnicalDebt{
+				Title:    "Outdated Library",
+				Author:   "Jane Doe",
+				Version:  "1.0.0",
+				Date:     "2024-04-15",
+				State:    "Identified",
+				Severity: "urgent",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Severity Not Canonical",
+			td: TechnicalDebt{
+				Title:    "Outdated Library",
+				Author:   "Jane Doe",
+				Version:  "1.0.0",
+				Date:     "2024-04-15",
+				State:    "Identified",
+				Severity: "HIGH",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := validateTechnicalDebt(tt.td); (err != nil) != tt.wantErr {
-				t.Errorf("validateTechnicalDebt() error = %v, wantErr %v", err, tt.wantErr)
+			if err := Validate(tt.td); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -94,7 +154,7 @@ func TestGenerateMarkdown(t *testing.T) {
 		Version:        "1.0.0",
 		Date:           "2024-04-15",
 		State:          "Analyzed",
-		Relations:      []string{"TDR-102", "TDR-103"},
+		Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
 		Summary:        "The library is outdated and causes security vulnerabilities.",
 		Context:        "Originally chosen for quick implementation.",
 		ImpactTech:     "Security risks and maintainability issues.",
@@ -187,14 +247,13 @@ Completion of the security audit.
 Training for the development team.
 `
 
-	result := generateMarkdown(td)
+	result := GenerateMarkdown(td)
 	if strings.TrimSpace(result) != strings.TrimSpace(expected) {
-		t.Errorf("generateMarkdown() = \n%s\n, want \n%s", result, expected)
+		t.Errorf("GenerateMarkdown() = \n%s\n, want \n%s", result, expected)
 	}
 }
 
-// Example excerpt from generate-td_test.go
-
+// TestGenerateASCII checks the generation of plain ASCII content
 func TestGenerateASCII(t *testing.T) {
 	td := TechnicalDebt{
 		Title:          "Outdated Library",
@@ -202,7 +261,7 @@ func TestGenerateASCII(t *testing.T) {
 		Version:        "1.0.0",
 		Date:           "2024-04-15",
 		State:          "Analyzed",
-		Relations:      []string{"TDR-102", "TDR-103"},
+		Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
 		Summary:        "The library is outdated and causes security vulnerabilities.",
 		Context:        "Originally chosen for quick implementation.",
 		ImpactTech:     "Security risks and maintainability issues.",
@@ -294,13 +353,13 @@ Additional Notes:
 Training for the development team.
 `
 
-	result := generateASCII(td)
+	result := GenerateASCII(td)
 	if strings.TrimSpace(result) != strings.TrimSpace(expected) {
-		t.Errorf("generateASCII() = \n%s\n, want \n%s", result, expected)
+		t.Errorf("GenerateASCII() = \n%s\n, want \n%s", result, expected)
 	}
 }
 
-// TestGeneratePDF checks the generation of a PDF file (basic existence check)
+// TestGeneratePDF checks the generation of a PDF document (basic existence check)
 func TestGeneratePDF(t *testing.T) {
 	td := TechnicalDebt{
 		Title:          "Outdated Library",
@@ -308,7 +367,7 @@ func TestGeneratePDF(t *testing.T) {
 		Version:        "1.0.0",
 		Date:           "2024-04-15",
 		State:          "Analyzed",
-		Relations:      []string{"TDR-102", "TDR-103"},
+		Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
 		Summary:        "The library is outdated and causes security vulnerabilities.",
 		Context:        "Originally chosen for quick implementation.",
 		ImpactTech:     "Security risks and maintainability issues.",
@@ -323,24 +382,86 @@ func TestGeneratePDF(t *testing.T) {
 		Additional:     "Training for the development team.",
 	}
 
-	filename := "test_output.pdf"
-
 	// Generate PDF
-	err := generatePDF(td, filename)
-	if err != nil {
-		t.Fatalf("generatePDF() failed: %v", err)
+	var buf bytes.Buffer
+	if err := (PDFRenderer{}).Render(&buf, td); err != nil {
+		t.Fatalf("PDFRenderer.Render() failed: %v", err)
+	}
+
+	// Check that a PDF document was written
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("PDFRenderer.Render() did not write a PDF document")
 	}
+}
 
-	// Check if the file exists
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		t.Errorf("generatePDF() failed to create the file: %s", filename)
+// TestPDFFonts checks that text outside Latin-1 is set in an embedded font and
+// that unusable font files are reported
+func TestPDFFonts(t *testing.T) {
+	td := TechnicalDebt{Title: "Veraltete Bibliothek: Größenänderung", Author: "Jürgen Müller", Summary: "Устаревшая библиотека, €, ≠"}
+
+	var buf bytes.Buffer
+	if err := (PDFRenderer{}).Render(&buf, td); err != nil {
+		t.Fatalf("PDFRenderer.Render() failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/FontFile2")) {
+		t.Errorf("PDFRenderer.Render() did not embed a TrueType font")
 	}
 
-	// Clean up
-	os.Remove(filename)
+	notFont := filepath.Join(t.TempDir(), "font.ttf")
+	if err := os.WriteFile(notFont, []byte("not a font"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []PDFRenderer{
+		{FontFile: filepath.Join(t.TempDir(), "missing.ttf")},
+		{BoldFontFile: filepath.Join(t.TempDir(), "missing.ttf")},
+		{FontFile: notFont},
+	} {
+		if err := r.Render(&bytes.Buffer{}, td); err == nil {
+			t.Errorf("%+v: Render() expected an error for an unusable font file", r)
+		}
+	}
 }
 
-// TestGenerateExcel checks the generation of an Excel file (basic existence check)
+// TestPDFLayout checks the page header and the "page X of Y" footer of a multi-page record
+func TestPDFLayout(t *testing.T) {
+	doc, err := PDFRenderer{}.newDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetCompression(false)
+	td := TechnicalDebt{ID: "TDR-0007", Title: "Outdated Library", State: "In Progress", Severity: "High",
+		Summary: strings.Repeat("The library is outdated. ", 400)}
+	doc.header = pdfHeader(td)
+	doc.AddPage()
+	doc.record(td)
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+
+	// Text set in a TrueType font is written as UTF-16
+	utf16 := func(s string) []byte {
+		var b []byte
+		for _, r := range s {
+			b = append(b, byte(r>>8), byte(r))
+		}
+		return b
+	}
+	pages := doc.PageNo()
+	if pages < 2 {
+		t.Fatalf("record has %d pages, want several", pages)
+	}
+	for _, want := range []string{fmt.Sprintf("Page 1 of %d", pages), fmt.Sprintf("Page %d of %d", pages, pages)} {
+		if !bytes.Contains(buf.Bytes(), utf16(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+	if got := bytes.Count(buf.Bytes(), utf16("TDR-0007 · In Progress")); got != pages {
+		t.Errorf("header found on %d pages, want %d", got, pages)
+	}
+}
+
+// TestGenerateExcel checks the generation of an Excel workbook (basic existence check)
 func TestGenerateExcel(t *testing.T) {
 	td := TechnicalDebt{
 		Title:          "Outdated Library",
@@ -348,7 +469,7 @@ func TestGenerateExcel(t *testing.T) {
 		Version:        "1.0.0",
 		Date:           "2024-04-15",
 		State:          "Analyzed",
-		Relations:      []string{"TDR-102", "TDR-103"},
+		Relations:      []Relation{{Target: "TDR-102"}, {Target: "TDR-103"}},
 		Summary:        "The library is outdated and causes security vulnerabilities.",
 		Context:        "Originally chosen for quick implementation.",
 		ImpactTech:     "Security risks and maintainability issues.",
@@ -363,19 +484,26 @@ func TestGenerateExcel(t *testing.T) {
 		Additional:     "Training for the development team.",
 	}
 
-	filename := "test_output.xlsx"
-
 	// Generate Excel
-	err := generateExcel(td, filename)
-	if err != nil {
-		t.Fatalf("generateExcel() failed: %v", err)
+	var buf bytes.Buffer
+	if err := (ExcelRenderer{}).Render(&buf, td); err != nil {
+		t.Fatalf("ExcelRenderer.Render() failed: %v", err)
 	}
 
-	// Check if the file exists
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		t.Errorf("generateExcel() failed to create the file: %s", filename)
+	// Check that a workbook (a zip archive) was written
+	if !bytes.HasPrefix(buf.Bytes(), []byte("PK")) {
+		t.Errorf("ExcelRenderer.Render() did not write an Excel workbook")
 	}
+}
 
-	// Clean up
-	os.Remove(filename)
+// TestLookup checks that every built-in format can be looked up by name
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"markdown", "ascii", "pdf", "excel", "PDF"} {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("Lookup(%q) found no renderer", name)
+		}
+	}
+	if _, ok := Lookup("docx"); ok {
+		t.Errorf("Lookup(\"docx\") unexpectedly found a renderer")
+	}
 }
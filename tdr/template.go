@@ -0,0 +1,132 @@
+package tdr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateDir is the directory inside ConfigDir holding the output templates of a repository
+const TemplateDir = "templates"
+
+// templateExtension is the file extension of output templates
+const templateExtension = ".tmpl"
+
+// TemplateRenderer renders a record with a Go text/template. The template is
+// executed with the TechnicalDebt as data; see templateFuncs for the functions
+// available in addition to the built-in ones.
+type TemplateRenderer struct {
+	name      string
+	extension string
+	template  *template.Template
+	// Links resolves relations to record files for the link function
+	Links LinkResolver
+}
+
+// Name implements Renderer
+func (r TemplateRenderer) Name() string { return r.name }
+
+// Extension implements Renderer
+func (r TemplateRenderer) Extension() string { return r.extension }
+
+// Render implements Renderer
+func (r TemplateRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	t, err := r.template.Clone()
+	if err != nil {
+		return err
+	}
+	t.Funcs(template.FuncMap{"link": func(id string) string {
+		name, _ := r.Links.resolve(id)
+		return name
+	}})
+	if err := t.Execute(w, td); err != nil {
+		return fmt.Errorf("error executing template %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// templateFuncs are the functions available to output templates
+var templateFuncs = template.FuncMap{
+	// default returns value, or fallback if value is empty, e.g. for the placeholders of empty templates
+	"default": func(fallback, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	},
+	// link returns the file name, without extension, of the record with the
+	// given ID, or "" if it is unknown; it is replaced in Render
+	"link":         func(id string) string { return "" },
+	"formatNumber": FormatNumber,
+	"upper":        strings.ToUpper,
+	"lower":        strings.ToLower,
+	"trim":         strings.TrimSpace,
+	"join":         strings.Join,
+	"tableCell":    escapeTableCell,
+	// indent prefixes every line of s with n spaces
+	"indent": func(n int, s string) string {
+		prefix := strings.Repeat(" ", n)
+		return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+	},
+}
+
+// NewTemplateRenderer parses text as an output template for the format name,
+// writing files with the given extension
+func NewTemplateRenderer(name, extension, text string) (TemplateRenderer, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return TemplateRenderer{}, fmt.Errorf("invalid template: %w", err)
+	}
+	return TemplateRenderer{name: strings.ToLower(name), extension: extension, template: t}, nil
+}
+
+// LoadTemplate reads an output template from a file named NAME.EXT.tmpl, e.g.
+// "confluence.md.tmpl" for the format "confluence" writing ".md" files.
+// Without an EXT the files are written as ".txt".
+func LoadTemplate(path string) (TemplateRenderer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TemplateRenderer{}, err
+	}
+	base := strings.TrimSuffix(filepath.Base(path), templateExtension)
+	extension := filepath.Ext(base)
+	name := strings.TrimSuffix(base, extension)
+	if extension == "" || name == "" {
+		name, extension = base, ".txt"
+	}
+	r, err := NewTemplateRenderer(name, extension, string(data))
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Templates loads the output templates in the TemplateDir of the repository,
+// ordered by name. A repository without templates has none.
+func (r *Repository) Templates() ([]TemplateRenderer, error) {
+	entries, err := os.ReadDir(filepath.Join(r.Root, ConfigDir, TemplateDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading templates: %w", err)
+	}
+	var templates []TemplateRenderer
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != templateExtension {
+			continue
+		}
+		t, err := LoadTemplate(filepath.Join(r.Root, ConfigDir, TemplateDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].name < templates[j].name })
+	return templates, nil
+}
@@ -0,0 +1,80 @@
+package tdr
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTemplateRenderer checks that templates see the record, the functions and the links
+func TestTemplateRenderer(t *testing.T) {
+	r, err := NewTemplateRenderer("Short", ".md", `{{.ID}} {{upper .Title}} {{default "unrated" .Severity}} {{formatNumber .EffortEstimate}}
+{{- range .Relations}} {{.Kind.Label}}={{link .Target}}{{end}}`)
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+	if r.Name() != "short" || r.Extension() != ".md" {
+		t.Errorf("renderer is %q with extension %q", r.Name(), r.Extension())
+	}
+
+	td := TechnicalDebt{ID: "TDR-0001", Title: "Outdated Library", EffortEstimate: 2.5,
+		Relations: []Relation{{Kind: Blocks, Target: "TDR-0002"}}}
+	links := func(id string) (string, bool) { return "0002-missing-tests", id == "TDR-0002" }
+	var buf bytes.Buffer
+	if err := WithLinks(r, links).Render(&buf, td); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "TDR-0001 OUTDATED LIBRARY unrated 2.5 Blocks=0002-missing-tests"; buf.String() != want {
+		t.Errorf("Render() = %q, want %q", buf.String(), want)
+	}
+
+	// Without links, link returns nothing
+	buf.Reset()
+	if err := r.Render(&buf, td); err != nil || buf.String() != "TDR-0001 OUTDATED LIBRARY unrated 2.5 Blocks=" {
+		t.Errorf("Render() = %q, %v", buf.String(), err)
+	}
+
+	if _, err := NewTemplateRenderer("broken", ".md", "{{.Title"); err == nil {
+		t.Errorf("NewTemplateRenderer() expected an error for a broken template")
+	}
+	r, _ = NewTemplateRenderer("unknown", ".md", "{{.Owner}}")
+	if err := r.Render(&bytes.Buffer{}, td); err == nil {
+		t.Errorf("Render() expected an error for an unknown field")
+	}
+}
+
+// TestRepositoryTemplates checks that templates are loaded from the repository and named after their files
+func TestRepositoryTemplates(t *testing.T) {
+	root := t.TempDir()
+	repo, err := InitRepository(root, "")
+	if err != nil {
+		t.Fatalf("InitRepository() error = %v", err)
+	}
+	if templates, err := repo.Templates(); err != nil || len(templates) != 0 {
+		t.Fatalf("Templates() = %v, %v, want none", templates, err)
+	}
+
+	dir := filepath.Join(root, ConfigDir, TemplateDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range map[string]string{
+		"wiki.md.tmpl":  "# {{.Title}}",
+		"plain.tmpl":    "{{.Title}}",
+		"notes.txt":     "not a template",
+		"report.tex.bk": "not a template either",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	templates, err := repo.Templates()
+	if err != nil {
+		t.Fatalf("Templates() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name() != "plain" || templates[0].Extension() != ".txt" ||
+		templates[1].Name() != "wiki" || templates[1].Extension() != ".md" {
+		t.Errorf("Templates() = %+v", templates)
+	}
+}
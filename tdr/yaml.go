@@ -0,0 +1,64 @@
+package tdr
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLRenderer renders a record as a YAML document
+type YAMLRenderer struct{}
+
+// Name implements Renderer
+func (YAMLRenderer) Name() string { return "yaml" }
+
+// Extension implements Renderer
+func (YAMLRenderer) Extension() string { return ".yaml" }
+
+// Render implements Renderer
+func (YAMLRenderer) Render(w io.Writer, td TechnicalDebt) error {
+	var doc any = NewDocument(td)
+	if td.Empty {
+		doc = yamlTemplate(td.CustomFields)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("error encoding YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// yamlTemplate returns a mapping listing every key, and the given custom
+// fields, with an empty value
+func yamlTemplate(fields Fields) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value *yaml.Node) {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+
+	add("schema_version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprint(SchemaVersion)})
+	for _, key := range recordKeys("yaml") {
+		switch {
+		case isFieldsKey(key):
+			custom := &yaml.Node{Kind: yaml.MappingNode}
+			if len(fields) == 0 {
+				custom.Style = yaml.FlowStyle
+			}
+			for _, field := range fields {
+				custom.Content = append(custom.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Name},
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""})
+			}
+			add(key, custom)
+		case isListKey(key):
+			add(key, &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle})
+		case isNumberKey(key):
+			add(key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"})
+		default:
+			add(key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""})
+		}
+	}
+	return node
+}